@@ -0,0 +1,399 @@
+package main
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"SOMAS2023/internal/server"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const usage = `Usage: SOMAS2023 [command] [flags]
+
+Commands:
+  run      run a simulation (default when no command is given)
+  sweep    run the same simulation once for each of a list of seeds
+  batch    run every parameter set of an experiment file with several seeds and aggregate the results
+  inspect  summarise a game dump, or print the resolved configuration
+
+Run "SOMAS2023 <command> -help" to list the flags of a command.
+`
+
+func runCLI(args []string) error {
+	command := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "run":
+		err = runCommand(args)
+	case "sweep":
+		err = sweepCommand(args)
+	case "batch":
+		err = batchCommand(args)
+	case "inspect":
+		err = inspectCommand(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		err = fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+	// the flag package has already printed the usage of the command
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// runOptions are the flags shared by every command that runs simulations
+type runOptions struct {
+	configPath      string
+	iterations      int
+	rounds          int
+	agentsPerTeam   int
+	teams           string
+	seed            int64
+	outputDirectory string
+	outputs         string
+	workers         int
+}
+
+func (o *runOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.configPath, "config", "", "YAML or JSON file overriding the default simulation parameters")
+	flags.IntVar(&o.iterations, "iterations", 10, "number of games to play")
+	flags.IntVar(&o.rounds, "rounds", 0, "rounds per game (overrides round_iterations)")
+	flags.IntVar(&o.agentsPerTeam, "agents-per-team", 0, "agents spawned for each team (overrides biker_agent_count)")
+	flags.StringVar(&o.teams, "teams", "", fmt.Sprintf(`agents spawned per team, e.g. "team1=10,team5=2" (overrides team_counts), teams: %s`, strings.Join(server.AgentTeamNames(), ", ")))
+	flags.Int64Var(&o.seed, "seed", 0, "seed for the random number generator (overrides seed, 0 picks one at random)")
+	flags.StringVar(&o.outputDirectory, "out", ".", "directory the outputs are written to")
+	flags.StringVar(&o.outputs, "outputs", "statistics,dump", `comma separated outputs to write: "statistics", "dump" or "none"`)
+}
+
+// registerWorkers adds the flag of the commands that run several simulations at once
+func (o *runOptions) registerWorkers(flags *flag.FlagSet) {
+	flags.IntVar(&o.workers, "workers", runtime.NumCPU(), "number of simulations run at the same time, the progress of each simulation is only printed with 1")
+}
+
+// simConfig loads the configuration file (if any) and applies the command line overrides
+func (o *runOptions) simConfig() (utils.SimConfig, error) {
+	config := utils.DefaultSimConfig()
+	if o.configPath != "" {
+		var err error
+		if config, err = utils.LoadSimConfig(o.configPath); err != nil {
+			return config, err
+		}
+	}
+	if o.rounds > 0 {
+		config.RoundIterations = o.rounds
+	}
+	if o.agentsPerTeam > 0 {
+		config.TeamCounts = make(map[string]int)
+		for _, team := range server.AgentTeamNames() {
+			config.TeamCounts[team] = o.agentsPerTeam
+		}
+	}
+	if o.teams != "" {
+		teamCounts, err := parseTeamCounts(o.teams)
+		if err != nil {
+			return config, err
+		}
+		config.TeamCounts = teamCounts
+	}
+	if o.seed != 0 {
+		config.Seed = o.seed
+	}
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if _, err := server.GetTeamCounts(config); err != nil {
+		return config, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return config, nil
+}
+
+// parseTeamCounts reads a comma separated list of team=count pairs
+func parseTeamCounts(list string) (map[string]int, error) {
+	teamCounts := make(map[string]int)
+	for _, item := range strings.Split(list, ",") {
+		team, countString, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid team count %q, expected team=count", item)
+		}
+		if !slices.Contains(server.AgentTeamNames(), team) {
+			return nil, fmt.Errorf("unknown team %q, teams: %s", team, strings.Join(server.AgentTeamNames(), ", "))
+		}
+		count, err := strconv.Atoi(countString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid team count %q: %w", item, err)
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("invalid team count %q, the count cannot be negative", item)
+		}
+		teamCounts[team] = count
+	}
+	return teamCounts, nil
+}
+
+func (o *runOptions) outputOptions(directory string) (server.OutputOptions, error) {
+	options := server.OutputOptions{Directory: directory}
+	// simulations running side by side would interleave their messages
+	if o.workers <= 1 {
+		options.Log = os.Stdout
+	}
+	for _, output := range strings.Split(o.outputs, ",") {
+		switch strings.TrimSpace(output) {
+		case "statistics":
+			options.Statistics = true
+		case "dump":
+			options.GameDump = true
+		case "none", "":
+		default:
+			return options, fmt.Errorf("unknown output %q", output)
+		}
+	}
+	return options, nil
+}
+
+func (o *runOptions) validate() error {
+	if o.iterations <= 0 {
+		return fmt.Errorf("iterations must be positive, got %d", o.iterations)
+	}
+	if o.workers < 0 {
+		return fmt.Errorf("workers cannot be negative, got %d", o.workers)
+	}
+	return nil
+}
+
+func runSimulation(options *runOptions, config utils.SimConfig, outputDirectory string) error {
+	outputOptions, err := options.outputOptions(outputDirectory)
+	if err != nil {
+		return err
+	}
+	s, err := server.InitializeWithConfig(options.iterations, config)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Simulation seed: %d\n", s.GetConfig().Seed)
+	s.SetOutputOptions(outputOptions)
+	return s.Run()
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	options := &runOptions{}
+	options.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := options.validate(); err != nil {
+		return err
+	}
+	config, err := options.simConfig()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Hello Agents")
+	return runSimulation(options, config, options.outputDirectory)
+}
+
+func sweepCommand(args []string) error {
+	flags := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	options := &runOptions{}
+	options.register(flags)
+	options.registerWorkers(flags)
+	seedList := flags.String("seeds", "1-10", `seeds to run, as a list ("1,4,9") and/or ranges of at most 10000 seeds ("1-10", "-5--1")`)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := options.validate(); err != nil {
+		return err
+	}
+	seeds, err := utils.ParseSeeds(*seedList)
+	if err != nil {
+		return err
+	}
+	config, err := options.simConfig()
+	if err != nil {
+		return err
+	}
+
+	simulations := make([]server.Simulation, len(seeds))
+	for i, seed := range seeds {
+		outputOptions, err := options.outputOptions(filepath.Join(options.outputDirectory, fmt.Sprintf("seed_%d", seed)))
+		if err != nil {
+			return err
+		}
+		config.Seed = seed
+		simulations[i] = server.Simulation{
+			Iterations: options.iterations,
+			Config:     config,
+			Output:     outputOptions,
+		}
+	}
+
+	fmt.Printf("Running %d seeds on %d workers\n", len(seeds), max(1, min(options.workers, len(seeds))))
+	results, err := server.RunSimulations(simulations, options.workers)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		fmt.Printf("Seed %d: survival rate %.3f, outputs written to %s\n", result.Seed, result.Summary.SurvivalRate, simulations[i].Output.Directory)
+	}
+	return nil
+}
+
+func batchCommand(args []string) error {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	options := &runOptions{}
+	flags.StringVar(&options.configPath, "config", "", "YAML or JSON file with the parameters shared by every simulation")
+	flags.StringVar(&options.outputDirectory, "out", ".", "directory the results tables are written to")
+	flags.StringVar(&options.outputs, "outputs", "none", `outputs to write for each simulation: "statistics", "dump" or "none"`)
+	options.registerWorkers(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: SOMAS2023 batch [flags] experiment.yaml")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("batch expects one experiment file")
+	}
+	if options.workers < 0 {
+		return fmt.Errorf("workers cannot be negative, got %d", options.workers)
+	}
+
+	experiment, err := server.LoadExperiment(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	config, err := options.simConfig()
+	if err != nil {
+		return err
+	}
+	outputOptions, err := options.outputOptions(options.outputDirectory)
+	if err != nil {
+		return err
+	}
+
+	runs, err := server.RunExperiment(experiment, config, outputOptions, options.workers)
+	if err != nil {
+		return err
+	}
+	if err := server.WriteExperimentResults(options.outputDirectory, runs); err != nil {
+		return err
+	}
+	fmt.Printf("\nResults of %d simulations written to %s\n\n", len(runs), filepath.Join(options.outputDirectory, server.ExperimentResultsFileName))
+	return printExperimentResults(server.AggregateRuns(runs))
+}
+
+// printExperimentResults prints the aggregated results table, one row per parameter set
+func printExperimentResults(results []server.ExperimentResult) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "Parameters\tSeeds\tAgents\tSurvival Rate\tLifetime\tEnergy\tPoints")
+	for _, result := range results {
+		parameters := result.Parameters.String()
+		if parameters == "" {
+			parameters = "(base)"
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%.3f ± %.3f\t%.2f ± %.2f\t%.3f ± %.3f\t%.2f ± %.2f\n",
+			parameters, result.Seeds, result.Mean.Agents,
+			result.Mean.SurvivalRate, result.StdDev.SurvivalRate,
+			result.Mean.Lifetime, result.StdDev.Lifetime,
+			result.Mean.Energy, result.StdDev.Energy,
+			result.Mean.Points, result.StdDev.Points)
+	}
+	return writer.Flush()
+}
+
+func inspectCommand(args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	configPath := flags.String("config", "", "print the configuration resolved from this file instead of a game dump")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: SOMAS2023 inspect [-config file] [game dump]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *configPath != "" {
+		config, err := utils.LoadSimConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(os.Stdout)
+		defer encoder.Close()
+		return encoder.Encode(config)
+	}
+
+	dumpPath := filepath.Join(".", server.GameDumpFileName)
+	if flags.NArg() > 0 {
+		dumpPath = flags.Arg(0)
+	}
+	gameStates, err := server.LoadGameDump(dumpPath)
+	if err != nil {
+		return err
+	}
+	return summariseGameDump(gameStates)
+}
+
+// summariseGameDump prints, for every game in the dump, how many agents survived and how each
+// group performed in the final round
+func summariseGameDump(gameStates []server.GameStateDump) error {
+	// every game starts with a dump of iteration -1 (before the first round)
+	games := make([][]server.GameStateDump, 0)
+	for _, gameState := range gameStates {
+		if gameState.Iteration == -1 || len(games) == 0 {
+			games = append(games, make([]server.GameStateDump, 0))
+		}
+		games[len(games)-1] = append(games[len(games)-1], gameState)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "Game\tRounds\tGroup\tAgents\tSurvivors\tAverage Points\tAverage Energy")
+	for i, game := range games {
+		initial, final := game[0], game[len(game)-1]
+		agentsPerGroup := make(map[int]int)
+		for _, agent := range initial.Agents {
+			agentsPerGroup[agent.GroupID]++
+		}
+		survivorsPerGroup := make(map[int]int)
+		pointsPerGroup := make(map[int]float64)
+		energyPerGroup := make(map[int]float64)
+		for _, agent := range final.Agents {
+			survivorsPerGroup[agent.GroupID]++
+			pointsPerGroup[agent.GroupID] += float64(agent.Points)
+			energyPerGroup[agent.GroupID] += agent.EnergyLevel
+		}
+
+		groups := make([]int, 0, len(agentsPerGroup))
+		for group := range agentsPerGroup {
+			groups = append(groups, group)
+		}
+		sort.Ints(groups)
+		for _, group := range groups {
+			survivors := survivorsPerGroup[group]
+			averagePoints, averageEnergy := 0.0, 0.0
+			if survivors > 0 {
+				averagePoints = pointsPerGroup[group] / float64(survivors)
+				averageEnergy = energyPerGroup[group] / float64(survivors)
+			}
+			fmt.Fprintf(writer, "%d\t%d\t%d\t%d\t%d\t%.2f\t%.2f\n", i, len(game)-1, group, agentsPerGroup[group], survivors, averagePoints, averageEnergy)
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"SOMAS2023/internal/server"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTeamCounts(t *testing.T) {
+	tests := []struct {
+		name  string
+		list  string
+		want  map[string]int
+		valid bool
+	}{
+		{"single team", "team1=10", map[string]int{"team1": 10}, true},
+		{"several teams", "team1=10, team5=2,team8=0", map[string]int{"team1": 10, "team5": 2, "team8": 0}, true},
+		{"missing count", "team1", nil, false},
+		{"empty count", "team1=", nil, false},
+		{"not a number", "team1=ten", nil, false},
+		{"trailing comma", "team1=10,", nil, false},
+		{"negative count", "team1=-1", nil, false},
+		{"unknown team", "team42=3", nil, false},
+		{"missing team", "=3", nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			teamCounts, err := parseTeamCounts(test.list)
+			if !test.valid {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, teamCounts)
+			}
+		})
+	}
+}
+
+func TestOutputOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		want  server.OutputOptions
+		valid bool
+	}{
+		{"default", []string{"-workers", "1"}, server.OutputOptions{Directory: ".", Statistics: true, GameDump: true, Log: os.Stdout}, true},
+		{"output directory", []string{"-workers", "1", "-out", "results"}, server.OutputOptions{Directory: "results", Statistics: true, GameDump: true, Log: os.Stdout}, true},
+		{"statistics only", []string{"-workers", "1", "-outputs", "statistics"}, server.OutputOptions{Directory: ".", Statistics: true, Log: os.Stdout}, true},
+		{"dump only", []string{"-workers", "0", "-outputs", " dump "}, server.OutputOptions{Directory: ".", GameDump: true, Log: os.Stdout}, true},
+		{"none", []string{"-workers", "1", "-outputs", "none"}, server.OutputOptions{Directory: ".", Log: os.Stdout}, true},
+		{"several workers", []string{"-workers", "4"}, server.OutputOptions{Directory: ".", Statistics: true, GameDump: true}, true},
+		{"unknown output", []string{"-outputs", "statistics,video"}, server.OutputOptions{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			options := &runOptions{}
+			options.register(flags)
+			options.registerWorkers(flags)
+			if err := flags.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+			outputOptions, err := options.outputOptions(options.outputDirectory)
+			if !test.valid {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, outputOptions)
+			}
+		})
+	}
+}
@@ -123,7 +123,8 @@ func (bb *Biker1) PickBestBike() uuid.UUID {
 	}
 	bestBike := bb.GetBike()
 	bestScore := scoreMap[bestBike]
-	for id, score := range scoreMap {
+	for _, id := range utils.SortedIDs(scoreMap) {
+		score := scoreMap[id]
 		if score > bestScore {
 			bestBike = id
 			bestScore = score
@@ -102,19 +102,12 @@ func (bb *Biker1) distanceToEnergy(distance float64, initialEnergy float64) floa
 
 // Finds all boxes within our reachable distance
 func (bb *Biker1) getAllReachableBoxes() []uuid.UUID {
-	currLocation := bb.GetLocation()
-	ourEnergy := bb.GetEnergyLevel()
-	lootBoxes := bb.GetGameState().GetLootBoxes()
-	reachableBoxes := make([]uuid.UUID, 0)
-	var currDist float64
-	for _, loot := range lootBoxes {
-		lootPos := loot.GetPosition()
-		currDist = bb.ComputeDistance(currLocation, lootPos)
-		_, distance := bb.energyToReachableDistance(ourEnergy, bb.GetBikeInstance())
-		if currDist < distance {
-			reachableBoxes = append(reachableBoxes, loot.GetID())
-		}
+	if len(bb.GetGameState().GetLootBoxes()) == 0 {
+		return []uuid.UUID{}
 	}
+	_, distance := bb.energyToReachableDistance(bb.GetEnergyLevel(), bb.GetBikeInstance())
+	reachableBoxes := bb.GetGameState().GetLootBoxesWithin(bb.GetLocation(), distance)
+	utils.SortIDs(reachableBoxes)
 	return reachableBoxes
 }
 
@@ -125,7 +118,9 @@ func (bb *Biker1) getNearestBox() uuid.UUID {
 	nearestBox := uuid.Nil
 	var currDist float64
 	initialized := false
-	for id, loot := range bb.GetGameState().GetLootBoxes() {
+	lootBoxes := bb.GetGameState().GetLootBoxes()
+	for _, id := range utils.SortedIDs(lootBoxes) {
+		loot := lootBoxes[id]
 		if !initialized {
 			nearestBox = id
 			initialized = true
@@ -148,7 +143,9 @@ func (bb *Biker1) nearestLootColour() (uuid.UUID, float64) {
 	currLocation := bb.GetLocation()
 	//default to nearest lootbox
 	var currDist float64
-	for id, loot := range bb.GetGameState().GetLootBoxes() {
+	lootBoxes := bb.GetGameState().GetLootBoxes()
+	for _, id := range utils.SortedIDs(lootBoxes) {
+		loot := lootBoxes[id]
 		if !initialized {
 			nearestBox = id
 			initialized = true
@@ -171,7 +168,8 @@ func (bb *Biker1) FindReachableBoxNearestToBox(nearestColourBox uuid.UUID) uuid.
 	ourLocation := bb.GetLocation()
 	var currDist float64
 	var ourDist float64
-	for _, loot := range boxes {
+	for _, id := range utils.SortedIDs(boxes) {
+		loot := boxes[id]
 		lootPos := loot.GetPosition()
 		currDist = physics.ComputeDistance(currBoxLocation, lootPos)
 		ourDist = physics.ComputeDistance(lootPos, ourLocation)
@@ -225,7 +223,8 @@ func (bb *Biker1) checkBoxNearColour(box uuid.UUID, energy float64) uuid.UUID {
 	lootBoxes := bb.GetGameState().GetLootBoxes()
 	boxPos := lootBoxes[box].GetPosition()
 	var currDist float64
-	for _, loot := range lootBoxes {
+	for _, id := range utils.SortedIDs(lootBoxes) {
+		loot := lootBoxes[id]
 		lootPos := loot.GetPosition()
 		currDist = physics.ComputeDistance(boxPos, lootPos)
 		_, distance := bb.energyToReachableDistance(energy, bb.GetBikeInstance())
@@ -307,7 +306,8 @@ func (bb *Biker1) FinalDirectionVote(proposals map[uuid.UUID]uuid.UUID) voting.L
 	}
 
 	// for every nominated box (D)
-	for proposer, proposal := range proposals {
+	for _, proposer := range utils.SortedIDs(proposals) {
+		proposal := proposals[proposer]
 		if maxDist < bb.distanceToBox(proposal) {
 			// if it is not reachable, ignore
 			continue
@@ -348,8 +348,8 @@ func (bb *Biker1) FinalDirectionVote(proposals map[uuid.UUID]uuid.UUID) voting.L
 
 	// normalise values
 	sum := 0.0
-	for _, value := range votes {
-		sum += value
+	for _, id := range utils.SortedIDs(votes) {
+		sum += votes[id]
 	}
 	for key := range votes {
 		votes[key] /= sum
@@ -357,7 +357,8 @@ func (bb *Biker1) FinalDirectionVote(proposals map[uuid.UUID]uuid.UUID) voting.L
 
 	maxVote := 0.0
 	var finalProposal uuid.UUID
-	for proposal, value := range votes {
+	for _, proposal := range utils.SortedIDs(votes) {
+		value := votes[proposal]
 		if value >= maxVote {
 			maxVote = value
 			finalProposal = proposal
@@ -96,7 +96,8 @@ func (bb *Biker1) GetAllAgents() []obj.IBaseBiker {
 
 	agentMap := gs.GetAgents()
 	agents := make([]obj.IBaseBiker, 0, len(agentMap))
-	for _, agent := range agentMap {
+	for _, id := range utils.SortedIDs(agentMap) {
+		agent := agentMap[id]
 		agents = append(agents, agent)
 	}
 	return agents
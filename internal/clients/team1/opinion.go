@@ -372,7 +372,7 @@ func (bb *Biker1) UpdateAllAgentsFairness(agents_to_update []obj.IBaseBiker) {
 
 		bikeID := bb.GetBike()
 		governance := bb.GetGameState().GetMegaBikes()[bikeID].GetGovernance()
-		if governance == 0 {
+		if governance == utils.Democracy || governance == utils.Council {
 			bb.UpdateFairness(id)
 		} else {
 			ruler := bb.GetGameState().GetMegaBikes()[bikeID].GetRuler()
@@ -8,7 +8,6 @@ import (
 	"SOMAS2023/internal/common/voting"
 	"fmt"
 	"maps"
-	"math/rand"
 
 	"github.com/google/uuid"
 )
@@ -127,7 +126,8 @@ func (a *AgentTwo) DecideDictatorAllocation() voting.IdVoteMap {
 
 	// Calculate the total social capital
 	totalSocialCapital := 0.0
-	for _, sc := range socialCapital {
+	for _, id := range utils.SortedIDs(socialCapital) {
+		sc := socialCapital[id]
 		totalSocialCapital += sc
 	}
 
@@ -203,7 +203,8 @@ func (a *AgentTwo) FinalDirectionVote(proposals map[uuid.UUID]uuid.UUID) voting.
 	// Assume we set our own social capital to 1.0, thus need to account for it
 	weight := 1.0 / (a.Modules.SocialCapital.GetSum(a.Modules.SocialCapital.SocialCapital) + 1)
 
-	for proposerID, proposal := range proposals {
+	for _, proposerID := range utils.SortedIDs(proposals) {
+		proposal := proposals[proposerID]
 		scWeight := 0.0
 		if proposerID == a.GetID() {
 			// If the proposal is our own, we vote for it with full weight
@@ -266,7 +267,7 @@ func (a *AgentTwo) DecideForce(direction uuid.UUID) {
 	}
 	// Use the average social capital to decide whether to pedal in the voted direciton or not
 	probabilityOfConformity := a.Modules.SocialCapital.GetAverage(a.Modules.SocialCapital.SocialCapital)
-	randomNumber := rand.Float64()
+	randomNumber := a.GetRand().Float64()
 	agentPosition := a.GetLocation()
 	lootboxID := direction
 	if randomNumber > probabilityOfConformity {
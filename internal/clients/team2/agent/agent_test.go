@@ -11,14 +11,14 @@ import (
 )
 
 func TestNewBaseTeam2Biker(t *testing.T) {
-	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New()))
+	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.NumOfColours, uuid.New(), utils.NewRand(1)))
 	assert.NotNil(t, agent)
 	assert.Equal(t, 0, agent.BaseBiker.GetPoints())
 	assert.Equal(t, 1.0, agent.BaseBiker.GetEnergyLevel())
 }
 
 func TestClippingSocialCapital(t *testing.T) {
-	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New()))
+	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.NumOfColours, uuid.New(), utils.NewRand(2)))
 	testAgentID := uuid.New()
 
 	// Set up predefined values for trust, institution, and network
@@ -29,7 +29,7 @@ func NewBaseTeam2Biker(baseBiker *objects.BaseBiker) *AgentTwo {
 	return &AgentTwo{
 		BaseBiker: baseBiker,
 		Modules: AgentModules{
-			Environment:    modules.GetEnvironmentModule(baseBiker.GetID(), baseBiker.GetGameState(), baseBiker.GetBike()),
+			Environment:    modules.GetEnvironmentModule(baseBiker.GetID(), baseBiker.GetGameState(), baseBiker.GetBike(), baseBiker.GetRand()),
 			SocialCapital:  modules.NewSocialCapital(),
 			Decision:       modules.NewDecisionModule(),
 			Utils:          modules.NewUtilsModule(),
@@ -20,7 +20,7 @@ func TestRuleAdherenceValue_SameDirection(t *testing.T) {
 	actualAction := utils.Forces{Pedal: 0.2, Turning: turningDecision}
 
 	// Create an instance of AgentTwo
-	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New()))
+	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.NumOfColours, uuid.New(), utils.NewRand(1)))
 
 	// Call the function
 	result := agent.Modules.Utils.RuleAdherenceValue(agentID, expectedAction, actualAction)
@@ -47,7 +47,7 @@ func TestRuleAdherenceValue_OppositeDirection(t *testing.T) {
 	actualAction := utils.Forces{Pedal: 0.2, Turning: ActualTurningDecision}
 
 	// Create an instance of AgentTwo
-	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New()))
+	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.NumOfColours, uuid.New(), utils.NewRand(2)))
 
 	// Call the function
 	result := agent.Modules.Utils.RuleAdherenceValue(agentID, expectedAction, actualAction)
@@ -74,7 +74,7 @@ func TestRuleAdherenceValue_OrthogonalDirection(t *testing.T) {
 	actualAction := utils.Forces{Pedal: 0.2, Turning: ActualTurningDecision}
 
 	// Create an instance of AgentTwo
-	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New()))
+	agent := NewBaseTeam2Biker(objects.GetBaseBiker(utils.NumOfColours, uuid.New(), utils.NewRand(3)))
 
 	// Call the function
 	result := agent.Modules.Utils.RuleAdherenceValue(agentID, expectedAction, actualAction)
@@ -18,6 +18,7 @@ type EnvironmentModule struct {
 	AgentId   uuid.UUID
 	GameState objects.IGameState
 	BikeId    uuid.UUID
+	Rand      *rand.Rand
 }
 
 ///
@@ -56,32 +57,21 @@ func (e *EnvironmentModule) GetLootBoxesByColor(color utils.Colour) map[uuid.UUI
 }
 
 func (e *EnvironmentModule) GetNearestLootbox(agentId uuid.UUID) uuid.UUID {
-	nearestLootbox := uuid.Nil
-	minDist := math.MaxFloat64
-	for _, lootbox := range e.GetLootBoxes() {
-		dist := e.GetDistanceToLootbox(lootbox.GetID())
-		if dist < minDist {
-			minDist = dist
-			nearestLootbox = lootbox.GetID()
-		}
+	if len(e.GetLootBoxes()) == 0 {
+		return uuid.Nil
 	}
-	return nearestLootbox
+	return e.GameState.GetNearestLootBoxes(e.GetBike().GetPosition(), 1)[0]
 }
 
 func (e *EnvironmentModule) GetNearestLootboxByColor(agentId uuid.UUID, color utils.Colour) uuid.UUID {
-	nearestLootbox := uuid.Nil
-	minDist := math.MaxFloat64
-	for _, lootbox := range e.GetLootBoxesByColor(color) {
-		dist := e.GetDistanceToLootbox(lootbox.GetID())
-		if dist < minDist {
-			minDist = dist
-			nearestLootbox = lootbox.GetID()
-		}
+	if len(e.GetLootBoxes()) == 0 {
+		return uuid.Nil
 	}
-	if nearestLootbox == uuid.Nil {
+	nearestLootboxes := e.GameState.GetNearestLootBoxesOfColour(e.GetBike().GetPosition(), 1, color)
+	if len(nearestLootboxes) == 0 {
 		return e.GetNearestLootbox(e.AgentId)
 	}
-	return nearestLootbox
+	return nearestLootboxes[0]
 }
 
 func (e *EnvironmentModule) GetDistanceToLootbox(lootboxId uuid.UUID) float64 {
@@ -95,7 +85,9 @@ func (e *EnvironmentModule) GetDistanceToLootbox(lootboxId uuid.UUID) float64 {
 func (e *EnvironmentModule) GetHighestGainLootbox() uuid.UUID {
 	bestGain := float64(0)
 	bestLoot := uuid.Nil
-	for _, lootboxId := range e.GetLootBoxes() {
+	lootBoxes := e.GetLootBoxes()
+	for _, id := range utils.SortedIDs(lootBoxes) {
+		lootboxId := lootBoxes[id]
 
 		gain := lootboxId.GetTotalResources() / e.GetDistanceToLootbox(lootboxId.GetID())
 		if gain > bestGain {
@@ -103,6 +95,10 @@ func (e *EnvironmentModule) GetHighestGainLootbox() uuid.UUID {
 			bestLoot = lootboxId.GetID()
 		}
 	}
+	// no lootbox looks worth anything (e.g. their resources are hidden), go for the nearest one
+	if bestLoot == uuid.Nil {
+		return e.GetNearestLootbox(e.AgentId)
+	}
 	return bestLoot
 }
 
@@ -122,7 +118,9 @@ func (e *EnvironmentModule) GetNearestLootboxAwayFromAudi() uuid.UUID {
 	// Find nearest lootbox away from audi.
 	minLoot := uuid.Nil
 	minDist := math.MaxFloat64
-	for id, lootbox := range e.GetLootBoxes() {
+	lootBoxes := e.GetLootBoxes()
+	for _, id := range utils.SortedIDs(lootBoxes) {
+		lootbox := lootBoxes[id]
 		dist := e.GetDistance(awayPos, lootbox.GetPosition())
 		if dist < minDist {
 			minDist = dist
@@ -191,7 +189,8 @@ func (e *EnvironmentModule) GetBikeWithMaximumSocialCapital(sc *SocialCapital) u
 	maxBikeId := uuid.Nil
 
 	bikes := e.GetBikes()
-	for bikeId, bike := range bikes {
+	for _, bikeId := range utils.SortedIDs(bikes) {
+		bike := bikes[bikeId]
 		totalSocialCapital := float64(0)
 		agentCount := float64(len(bike.GetAgents()))
 
@@ -216,14 +215,10 @@ func (e *EnvironmentModule) GetBikeWithMaximumSocialCapital(sc *SocialCapital) u
 		return maxBikeId
 	} else {
 		// Otherwise, change to a random bike.
-		i, targetI := 0, rand.Intn(len(bikes))
-		for id := range bikes {
-			if i == targetI {
-				return id
-			}
-			i++
+		if len(bikes) == 0 {
+			panic("No bikes found to change to.")
 		}
-		panic("No bikes found to change to.")
+		return utils.SortedIDs(bikes)[e.Rand.Intn(len(bikes))]
 	}
 }
 
@@ -261,10 +256,11 @@ func (e *EnvironmentModule) GetDistance(pos1, pos2 utils.Coordinates) float64 {
 	return math.Sqrt(math.Pow(pos1.X-pos2.X, 2) + math.Pow(pos1.Y-pos2.Y, 2))
 }
 
-func GetEnvironmentModule(agentId uuid.UUID, gameState objects.IGameState, bikeId uuid.UUID) *EnvironmentModule {
+func GetEnvironmentModule(agentId uuid.UUID, gameState objects.IGameState, bikeId uuid.UUID, rng *rand.Rand) *EnvironmentModule {
 	return &EnvironmentModule{
 		AgentId:   agentId,
 		GameState: gameState,
 		BikeId:    bikeId,
+		Rand:      rng,
 	}
 }
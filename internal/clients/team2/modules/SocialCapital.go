@@ -1,6 +1,7 @@
 package modules
 
 import (
+	"SOMAS2023/internal/common/utils"
 	"fmt"
 	"math"
 
@@ -21,16 +22,16 @@ func (sc *SocialCapital) GetAverage(scComponent map[uuid.UUID]float64) float64 {
 		return 0.5
 	}
 	var sum = 0.0
-	for _, value := range scComponent {
-		sum += value
+	for _, id := range utils.SortedIDs(scComponent) {
+		sum += scComponent[id]
 	}
 	return sum / float64(len(scComponent))
 }
 
 func (sc *SocialCapital) GetSum(scComponent map[uuid.UUID]float64) float64 {
 	var sum = 0.0
-	for _, value := range scComponent {
-		sum += value
+	for _, id := range utils.SortedIDs(scComponent) {
+		sum += scComponent[id]
 	}
 	return sum
 }
@@ -38,7 +39,8 @@ func (sc *SocialCapital) GetSum(scComponent map[uuid.UUID]float64) float64 {
 func (sc *SocialCapital) GetMinimumSocialCapital() (uuid.UUID, float64) {
 	min := math.MaxFloat64
 	minAgentId := uuid.Nil
-	for agentId, value := range sc.Reputation {
+	for _, agentId := range utils.SortedIDs(sc.Reputation) {
+		value := sc.Reputation[agentId]
 		if sc.SocialCapital[agentId] < min {
 			min = value
 			minAgentId = agentId
@@ -50,7 +52,8 @@ func (sc *SocialCapital) GetMinimumSocialCapital() (uuid.UUID, float64) {
 func (sc *SocialCapital) GetMaximumSocialCapital() (uuid.UUID, float64) {
 	max := 0.0
 	maxAgentId := uuid.Nil
-	for agentId, value := range sc.SocialCapital {
+	for _, agentId := range utils.SortedIDs(sc.SocialCapital) {
+		value := sc.SocialCapital[agentId]
 		if sc.SocialCapital[agentId] > max {
 			max = value
 			maxAgentId = agentId
@@ -147,11 +147,12 @@ func (t5 *team5Agent) DecideJoining(pendingAgents []uuid.UUID) map[uuid.UUID]boo
 	}
 
 	var ss []kv
-	for k, v := range pendingAgentUtility {
+	for _, k := range utils.SortedIDs(pendingAgentUtility) {
+		v := pendingAgentUtility[k]
 		ss = append(ss, kv{k, v})
 	}
 
-	sort.Slice(ss, func(i, j int) bool {
+	sort.SliceStable(ss, func(i, j int) bool {
 		return ss[i].Value > ss[j].Value // Sorting in descending order
 	})
 
@@ -41,7 +41,7 @@ func SortPreferences(prefs map[uuid.UUID]float64) map[uuid.UUID]float64 {
 	finalVotes := make(map[uuid.UUID]float64)
 	ids := make([]uuid.UUID, 0, len(prefs))
 
-	for id := range prefs {
+	for _, id := range utils.SortedIDs(prefs) {
 		ids = append(ids, id)
 	}
 
@@ -60,8 +60,8 @@ func normaliseMap(m map[uuid.UUID]float64) map[uuid.UUID]float64 {
 
 func sumMap(m map[uuid.UUID]float64) float64 {
 	var sum float64 = 0
-	for _, val := range m {
-		sum += val
+	for _, id := range utils.SortedIDs(m) {
+		sum += m[id]
 	}
 
 	return sum
@@ -48,7 +48,8 @@ func (t5 *team5Agent) ProposeDirection() uuid.UUID {
 	var max = 0.0
 	var prefLootId uuid.UUID
 
-	for lootId, preference := range preferenceMap {
+	for _, lootId := range utils.SortedIDs(preferenceMap) {
+		preference := preferenceMap[lootId]
 		if preference > max {
 			prefLootId = lootId
 			max = preference
@@ -3,6 +3,7 @@ package team5Agent
 import (
 	// Assuming this package contains the IMegaBike interface
 
+	"SOMAS2023/internal/common/utils"
 	"math"
 
 	"github.com/google/uuid"
@@ -126,7 +127,8 @@ func (t5 *team5Agent) getAverageEnergyOfAgents() float64 {
 	megaBikes := t5.GetGameState().GetMegaBikes()
 	var totalEnergy float64
 	var totalAgents float64
-	for _, megaBike := range megaBikes {
+	for _, id := range utils.SortedIDs(megaBikes) {
+		megaBike := megaBikes[id]
 		agents := megaBike.GetAgents()
 		for _, agent := range agents {
 			totalEnergy += agent.GetEnergyLevel()
@@ -140,7 +142,8 @@ func (t5 *team5Agent) getAverageForceOfAgents() float64 {
 	megaBikes := t5.GetGameState().GetMegaBikes()
 	var totalForce float64
 	var totalAgents float64
-	for _, megaBike := range megaBikes {
+	for _, id := range utils.SortedIDs(megaBikes) {
+		megaBike := megaBikes[id]
 		agents := megaBike.GetAgents()
 		for _, agent := range agents {
 			forceOfAgent := agent.GetForces().Pedal
@@ -69,7 +69,8 @@ func (t5 *team5Agent) ChangeBike() uuid.UUID {
 	//get ID for maximum reputation bike if the bike is not full (<8 agents)
 	maxRep := 0.0
 	maxRepID := currentBikeId
-	for bikeID, rep := range bikeReps {
+	for _, bikeID := range utils.SortedIDs(bikeReps) {
+		rep := bikeReps[bikeID]
 		//get length from GetAgents()
 		numAgentsOnbike := len(t5.GetGameState().GetMegaBikes()[bikeID].GetAgents())
 		if rep > maxRep && numAgentsOnbike < 8 {
@@ -7,6 +7,7 @@ import (
 
 	"SOMAS2023/internal/common/voting"
 	"math"
+	"sort"
 
 	"github.com/google/uuid"
 )
@@ -168,7 +169,8 @@ func (bb *Agent8) ChangeBike() uuid.UUID {
 	// Find the bike with the highest Borda score
 	var highestBordaScore float64
 	var winningBikeID uuid.UUID
-	for bikeID, score := range bordaScores {
+	for _, bikeID := range utils.SortedIDs(bordaScores) {
+		score := bordaScores[bikeID]
 		if score > highestBordaScore && acceptBool[bikeID] {
 			highestBordaScore = score
 			winningBikeID = bikeID
@@ -187,7 +189,13 @@ func (bb *Agent8) DecideAction() objects.BikerAction {
 	var loopNum = 0.0
 
 	// calculate total reflection score for current bike
-	for _, scoremap := range bb.loopScore {
+	loops := make([]int, 0, len(bb.loopScore))
+	for loop := range bb.loopScore {
+		loops = append(loops, loop)
+	}
+	sort.Ints(loops)
+	for _, loop := range loops {
+		scoremap := bb.loopScore[loop]
 		for bikeid, score := range scoremap {
 			if bikeid == selfBikeId {
 				selfBikeScore += score
@@ -2,6 +2,7 @@ package team8
 
 import (
 	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/common/voting"
 	"math"
 
@@ -302,7 +303,8 @@ func (bb *Agent8) HandleVoteRulerMessage(msg objects.VoteRulerMessage) {
 	firstRuler := uuid.Nil
 	bestScore := 0.0
 	chooseMe := false
-	for agent, score := range voteMap {
+	for _, agent := range utils.SortedIDs(voteMap) {
+		score := voteMap[agent]
 		if agent == bb.GetID() {
 			chooseMe = true
 		}
@@ -24,8 +24,8 @@ func CalculateGiniIndexFromAB(A, B float64) float64 {
 
 func softmax(preferences map[uuid.UUID]float64) map[uuid.UUID]float64 {
 	sum := 0.0
-	for _, pref := range preferences {
-		sum += math.Exp(pref)
+	for _, id := range utils.SortedIDs(preferences) {
+		sum += math.Exp(preferences[id])
 	}
 
 	softmaxPreferences := make(map[uuid.UUID]float64)
@@ -57,11 +57,11 @@ func rankByPreference(preferences map[uuid.UUID]float64) []uuid.UUID {
 	}
 
 	var sorted []kv
-	for id, pref := range preferences {
-		sorted = append(sorted, kv{id, pref})
+	for _, id := range utils.SortedIDs(preferences) {
+		sorted = append(sorted, kv{id, preferences[id]})
 	}
 
-	sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
 		return sorted[i].Preference > sorted[j].Preference
 	})
 
@@ -140,7 +140,9 @@ func (bb *Agent8) CountAgentsWithSameColour(bikeID uuid.UUID) int {
 func (bb *Agent8) GetAverageReputation(agent objects.IBaseBiker) float64 {
 	averageReputation := 0.0
 	agentNum := 0
-	for _, reputation := range agent.GetReputation() {
+	reputations := agent.GetReputation()
+	for _, id := range utils.SortedIDs(reputations) {
+		reputation := reputations[id]
 		averageReputation += reputation
 		if reputation != 0 {
 			agentNum++
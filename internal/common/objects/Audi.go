@@ -3,7 +3,7 @@ package objects
 import (
 	phy "SOMAS2023/internal/common/physics"
 	"SOMAS2023/internal/common/utils"
-	"math"
+	"math/rand"
 
 	"github.com/google/uuid"
 )
@@ -12,36 +12,50 @@ type IAudi interface {
 	IPhysicsObject
 	UpdateGameState(state IGameState)
 	GetTargetID() uuid.UUID
+	SetStrategy(strategy IAudiStrategy)
+	GetCooldown() int
+	SetCooldown(rounds int)
 }
 
 type Audi struct {
 	*PhysicsObject
 	target    IMegaBike
 	gameState IGameState
+	config    utils.SimConfig
+	strategy  IAudiStrategy
+	cooldown  int // rounds left before the audi chases bikes again
 }
 
 // GetAudi is a constructor for Audi that initializes it with a new UUID and default position.
-func GetAudi() *Audi {
+// The audi targets bikes using the strategy of the config.
+func GetAudi(config utils.SimConfig, rng *rand.Rand) *Audi {
 	return &Audi{
-		PhysicsObject: GetPhysicsObject(utils.MassAudi),
+		PhysicsObject: GetPhysicsObject(config.MassAudi, config, rng),
+		config:        config,
+		strategy:      GetAudiStrategy(config, rng),
 	}
 }
 
-func GetIAudi() IAudi {
-	return &Audi{
-		PhysicsObject: GetPhysicsObject(utils.MassAudi),
-	}
+func GetIAudi(config utils.SimConfig, rng *rand.Rand) IAudi {
+	return GetAudi(config, rng)
 }
 
 // Calculates and returns the desired force of the audi based on the current gamestate
 func (audi *Audi) UpdateForce() {
+	// the audi idles while it cools down after a hit
+	if audi.cooldown > 0 {
+		audi.target = nil
+		audi.force = 0.0
+		return
+	}
+
 	// Compute the target Megabike, which will update audi.target
 	audi.ComputeTarget()
 
 	if audi.target == nil { // no target, audi will not apply a force and eventually come to a stop
 		audi.force = 0.0
 	} else {
-		audi.force = utils.AudiMaxForce // Otherwise apply max force to get to target MegaBike
+		audi.force = audi.config.AudiMaxForce // Otherwise apply max force to get to target MegaBike
 	}
 }
 
@@ -50,47 +64,28 @@ func (audi *Audi) UpdateOrientation() {
 	// If no target, audi will not change orientation
 	// Otherwise, new orientation is calculated based on positioning of target
 	if audi.target != nil {
-		audi.orientation = phy.ComputeOrientation(audi.coordinates, audi.target.GetPosition())
+		aim := audi.target.GetPosition()
+		if audi.config.AudiPursuit == utils.InterceptPursuit {
+			// lead the target, the audi keeps heading for it if it cannot catch it
+			aim, _ = audi.boundary.InterceptPoint(audi.coordinates, audi.velocity, aim, audi.target.GetVelocity(), audi.target.GetOrientation())
+		}
+		desired := audi.boundary.ComputeOrientation(audi.coordinates, aim)
+		audi.orientation = phy.Turn(audi.orientation, desired, audi.config.AudiMaxTurnRate)
 	}
 }
 
-// Computes the target Megabike based on current gameState
+// Computes the target Megabike based on current gameState, using the targeting strategy of the audi
 func (audi *Audi) ComputeTarget() {
-	// search for target
-	minDistance := math.Inf(1)
-	minVelocity := math.Inf(1)
-	audi.target = nil
-	for _, bike := range audi.gameState.GetMegaBikes() {
-		if utils.AudiOnlyTargetsStationaryMegaBike {
-			if bike.GetVelocity() != 0.0 {
-				continue
-			}
-		}
-
-		if !utils.AudiTargetsEmptyMegaBike {
-			agentsOnBike := bike.GetAgents()
-			if len(agentsOnBike) == 0 {
-				continue
-			}
-		}
-
-		// ignore faster bike
-		if bike.GetVelocity() > minVelocity {
+	megaBikes := audi.gameState.GetMegaBikes()
+	candidates := make([]IMegaBike, 0, len(megaBikes))
+	for _, bikeID := range utils.SortedIDs(megaBikes) {
+		bike := megaBikes[bikeID]
+		if !audi.config.AudiTargetsEmptyMegaBike && len(bike.GetAgents()) == 0 {
 			continue
 		}
-
-		distance := phy.ComputeDistance(audi.coordinates, bike.GetPosition())
-		// minimize the velocity first
-		if bike.GetVelocity() < minVelocity {
-			audi.target = bike
-		} else if distance < minDistance { // if same velocity, then minimize distance
-			audi.target = bike
-		} else {
-			continue
-		}
-		minVelocity = audi.target.GetVelocity()
-		minDistance = distance
+		candidates = append(candidates, bike)
 	}
+	audi.target = audi.strategy.ChooseTarget(audi, candidates)
 }
 
 // Updates gameState member variable
@@ -98,6 +93,21 @@ func (audi *Audi) UpdateGameState(state IGameState) {
 	audi.gameState = state
 }
 
+// Replaces the targeting strategy of the audi
+func (audi *Audi) SetStrategy(strategy IAudiStrategy) {
+	audi.strategy = strategy
+}
+
+// GetCooldown returns the number of rounds the audi stays idle for
+func (audi *Audi) GetCooldown() int {
+	return audi.cooldown
+}
+
+// SetCooldown makes the audi idle for a number of rounds
+func (audi *Audi) SetCooldown(rounds int) {
+	audi.cooldown = max(0, rounds)
+}
+
 func (audi *Audi) GetTargetID() uuid.UUID {
 	if audi.target != nil {
 		return audi.target.GetID()
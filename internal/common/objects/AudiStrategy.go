@@ -0,0 +1,179 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"bytes"
+	"math"
+	"math/rand"
+
+	"github.com/google/uuid"
+)
+
+/*
+An IAudiStrategy decides which megabike the Audi chases. The Audi asks its strategy for a target every
+round, passing the bikes it can target sorted by ID.
+*/
+
+type IAudiStrategy interface {
+	// returns the bike the audi should chase amongst the candidates, or nil if it should not chase any
+	ChooseTarget(audi *Audi, candidates []IMegaBike) IMegaBike
+}
+
+// GetAudiStrategy is a constructor for the targeting strategies that can be chosen in the simulation config.
+// The random strategy draws from rng.
+func GetAudiStrategy(config utils.SimConfig, rng *rand.Rand) IAudiStrategy {
+	switch config.AudiStrategy {
+	case utils.TargetNearest:
+		return &NearestStrategy{}
+	case utils.TargetRichest:
+		return &RichestStrategy{}
+	case utils.TargetMostPopulated:
+		return &MostPopulatedStrategy{}
+	case utils.TargetRandom:
+		return &RandomStrategy{rng: rng}
+	case utils.TargetRoundRobin:
+		return &RoundRobinStrategy{}
+	default:
+		return &SlowestStrategy{onlyStationary: config.AudiOnlyTargetsStationaryMegaBike}
+	}
+}
+
+// SlowestStrategy targets the slowest bike, and the nearest one amongst bikes going equally slow
+type SlowestStrategy struct {
+	onlyStationary bool // only target stationary bikes
+}
+
+func (ss *SlowestStrategy) ChooseTarget(audi *Audi, candidates []IMegaBike) IMegaBike {
+	var target IMegaBike
+	minDistance := math.Inf(1)
+	minVelocity := math.Inf(1)
+	for _, bike := range candidates {
+		if ss.onlyStationary && bike.GetVelocity() != 0.0 {
+			continue
+		}
+		// ignore faster bike
+		if bike.GetVelocity() > minVelocity {
+			continue
+		}
+
+		distance := audi.boundary.ComputeDistance(audi.coordinates, bike.GetPosition())
+		// minimize the velocity first
+		if bike.GetVelocity() < minVelocity {
+			target = bike
+		} else if distance < minDistance { // if same velocity, then minimize distance
+			target = bike
+		} else {
+			continue
+		}
+		minVelocity = target.GetVelocity()
+		minDistance = distance
+	}
+	return target
+}
+
+// NearestStrategy targets the nearest bike
+type NearestStrategy struct{}
+
+func (ns *NearestStrategy) ChooseTarget(audi *Audi, candidates []IMegaBike) IMegaBike {
+	return mostValuable(audi, candidates, func(IMegaBike) float64 { return 0.0 })
+}
+
+// RichestStrategy targets the bike whose riders have the most points, and the nearest one amongst equally rich bikes
+type RichestStrategy struct{}
+
+func (rs *RichestStrategy) ChooseTarget(audi *Audi, candidates []IMegaBike) IMegaBike {
+	return mostValuable(audi, candidates, func(bike IMegaBike) float64 {
+		points := 0
+		for _, agent := range bike.GetAgents() {
+			points += agent.GetPoints()
+		}
+		return float64(points)
+	})
+}
+
+// MostPopulatedStrategy targets the bike with the most riders, and the nearest one amongst equally populated bikes
+type MostPopulatedStrategy struct{}
+
+func (ms *MostPopulatedStrategy) ChooseTarget(audi *Audi, candidates []IMegaBike) IMegaBike {
+	return mostValuable(audi, candidates, func(bike IMegaBike) float64 {
+		return float64(len(bike.GetAgents()))
+	})
+}
+
+// RandomStrategy chases a bike picked at random until it reaches it or the bike can no longer be targeted
+type RandomStrategy struct {
+	rng *rand.Rand
+}
+
+func (rs *RandomStrategy) ChooseTarget(audi *Audi, candidates []IMegaBike) IMegaBike {
+	if target := keepChasing(audi, candidates); target != nil {
+		return target
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[rs.rng.Intn(len(candidates))]
+}
+
+// RoundRobinStrategy chases the bikes in turn (in the order of their IDs), moving on to the next bike when it
+// reaches its target or the target can no longer be targeted. It remembers the last bike it chased, as the audi
+// forgets its target while it cools down after a hit.
+type RoundRobinStrategy struct {
+	last uuid.UUID // last bike chased
+}
+
+func (rr *RoundRobinStrategy) ChooseTarget(audi *Audi, candidates []IMegaBike) IMegaBike {
+	if target := keepChasing(audi, candidates); target != nil {
+		rr.last = target.GetID()
+		return target
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	target := candidates[0]
+	if rr.last != uuid.Nil {
+		for _, bike := range candidates {
+			if id := bike.GetID(); bytes.Compare(id[:], rr.last[:]) > 0 {
+				target = bike
+				break
+			}
+		}
+	}
+	rr.last = target.GetID()
+	return target
+}
+
+// mostValuable returns the candidate with the highest value, then the nearest one
+func mostValuable(audi *Audi, candidates []IMegaBike, value func(IMegaBike) float64) IMegaBike {
+	var target IMegaBike
+	maxValue := math.Inf(-1)
+	minDistance := math.Inf(1)
+	for _, bike := range candidates {
+		bikeValue := value(bike)
+		distance := audi.boundary.ComputeDistance(audi.coordinates, bike.GetPosition())
+		if bikeValue > maxValue || (bikeValue == maxValue && distance < minDistance) {
+			target = bike
+			maxValue = bikeValue
+			minDistance = distance
+		}
+	}
+	return target
+}
+
+// keepChasing returns the current target of the audi if it is still a candidate and the audi has not reached it yet
+func keepChasing(audi *Audi, candidates []IMegaBike) IMegaBike {
+	if audi.target == nil {
+		return nil
+	}
+	for _, bike := range candidates {
+		if bike.GetID() != audi.target.GetID() {
+			continue
+		}
+		distance := audi.boundary.ComputeDistance(audi.coordinates, bike.GetPosition())
+		if distance < math.Pow(audi.collisionThreshold, 2) {
+			return nil
+		}
+		return bike
+	}
+	return nil
+}
@@ -23,13 +23,23 @@ type IBaseBiker interface {
 	ProposeDirection() uuid.UUID                                                // ** returns the id of the desired lootbox based on internal strategy
 	FinalDirectionVote(proposals map[uuid.UUID]uuid.UUID) voting.LootboxVoteMap // ** stage 3 of direction voting
 	DecideAllocation() voting.IdVoteMap                                         // ** decide the allocation parameters
+	DecideShieldContribution() float64                                          // ** energy put into the shield of the bike against the audi this round
+	DecideLootClaim(lootBox uuid.UUID, rivals []uuid.UUID) float64              // ** fraction of a lootbox the bike should claim from rival bikes that reached it at the same time
+	PetitionReferendum() bool                                                   // ** whether the agent asks for a referendum on the governance of its bike this round
+	VoteGovernance() voting.GovernanceVote                                      // ** distribution of the agent's vote in a governance referendum
+	ProposeAmendment(constitution Constitution) (Constitution, bool)            // ** amendment to the constitution of its bike the agent puts to the vote, if any
+	VoteAmendment(constitution Constitution, amendment Constitution) bool       // ** whether the agent votes for an amendment to the constitution of its bike
+	DecideImpeachment() bool                                                    // ** whether the agent has no confidence in the ruler of its bike, any such vote calls an impeachment vote
+	VoteForSanction() map[uuid.UUID]int                                         // ** riders the agent votes to sanction, in a democracy or as a council member
 	VoteForKickout() map[uuid.UUID]int
 	VoteDictator() voting.IdVoteMap
 	VoteLeader() voting.IdVoteMap
+	VoteCouncil() voting.IdVoteMap // ** vote for the riders to sit on the council of the bike, the most voted riders are elected
 
 	// dictator functions
 	DictateDirection() uuid.UUID                // ** called only when the agent is the dictator
 	DecideKickOut() []uuid.UUID                 // ** decide which agents to kick out (dictator)
+	DecideSanctions() []uuid.UUID               // ** decide which agents to sanction (leader or dictator)
 	DecideDictatorAllocation() voting.IdVoteMap // ** decide the allocation (dictator)
 
 	// leader functions
@@ -79,7 +89,8 @@ const (
 )
 
 type BaseBiker struct {
-	*baseAgent.BaseAgent[IBaseBiker]              // BaseBiker inherits functions from BaseAgent such as GetID(), GetAllMessages() and UpdateAgentInternalState()
+	*baseAgent.BaseAgent[IBaseBiker]              // BaseBiker inherits functions from BaseAgent such as GetAllMessages() and UpdateAgentInternalState()
+	id                               uuid.UUID    // replaces the BaseAgent ID, which is drawn from the uuid package's global generator
 	soughtColour                     utils.Colour // the colour of the lootbox that the agent is currently seeking
 	onBike                           bool
 	energyLevel                      float64 // float between 0 and 1
@@ -89,6 +100,11 @@ type BaseBiker struct {
 	gameState                        IGameState            // updated by the server at every round
 	reputation                       map[uuid.UUID]float64 // record reputation for other agents in float
 	GroupID                          int
+	rng                              *rand.Rand // source of the biker's random decisions, shared with the server
+}
+
+func (bb *BaseBiker) GetID() uuid.UUID {
+	return bb.id
 }
 
 func (bb *BaseBiker) GetEnergyLevel() float64 {
@@ -140,19 +156,11 @@ func (bb *BaseBiker) GetLocation() utils.Coordinates {
 // in the MVP this is used to determine the pedalling forces as all agent will be
 // aiming to get to the closest lootbox by default
 func (bb *BaseBiker) nearestLoot() uuid.UUID {
-	currLocation := bb.GetLocation()
-	shortestDist := math.MaxFloat64
-	var nearestBox uuid.UUID
-	var currDist float64
-	for _, loot := range bb.gameState.GetLootBoxes() {
-		x, y := loot.GetPosition().X, loot.GetPosition().Y
-		currDist = math.Sqrt(math.Pow(currLocation.X-x, 2) + math.Pow(currLocation.Y-y, 2))
-		if currDist < shortestDist {
-			nearestBox = loot.GetID()
-			shortestDist = currDist
-		}
+	nearest := bb.gameState.GetNearestLootBoxes(bb.GetLocation(), 1)
+	if len(nearest) == 0 {
+		return uuid.Nil
 	}
-	return nearestBox
+	return nearest[0]
 }
 
 // in the MVP the biker's action defaults to pedaling (as it won't be able to change bikes)
@@ -162,6 +170,46 @@ func (bb *BaseBiker) DecideAction() BikerAction {
 	return Pedal
 }
 
+// in the MVP bikers do not put any energy into the shield of their bike
+func (bb *BaseBiker) DecideShieldContribution() float64 {
+	return 0.0
+}
+
+// in the MVP bikers claim a fair share of a contested lootbox
+func (bb *BaseBiker) DecideLootClaim(lootBox uuid.UUID, rivals []uuid.UUID) float64 {
+	return 1.0 / float64(len(rivals)+1)
+}
+
+// in the MVP bikers never ask for a referendum
+func (bb *BaseBiker) PetitionReferendum() bool {
+	return false
+}
+
+// in the MVP bikers vote for the governance they would found a bike with
+func (bb *BaseBiker) VoteGovernance() voting.GovernanceVote {
+	return voting.GovernanceVote{bb.DecideGovernance(): 1.0}
+}
+
+// in the MVP bikers do not propose amendments
+func (bb *BaseBiker) ProposeAmendment(constitution Constitution) (Constitution, bool) {
+	return constitution, false
+}
+
+// in the MVP bikers keep the constitution they have
+func (bb *BaseBiker) VoteAmendment(constitution Constitution, amendment Constitution) bool {
+	return false
+}
+
+// in the MVP bikers trust their ruler
+func (bb *BaseBiker) DecideImpeachment() bool {
+	return false
+}
+
+// in the MVP bikers sanction no one
+func (bb *BaseBiker) VoteForSanction() map[uuid.UUID]int {
+	return make(map[uuid.UUID]int)
+}
+
 // determine the forces (pedalling, breaking and turning)
 // in the MVP the pedalling force will be 1, the breaking 0 and the tunring is determined by the
 // location of the nearest lootbox
@@ -230,15 +278,10 @@ func (bb *BaseBiker) DecideForce(direction uuid.UUID) {
 // decide which bike to go to. the base agent chooses a random bike
 func (bb *BaseBiker) ChangeBike() uuid.UUID {
 	megaBikes := bb.gameState.GetMegaBikes()
-	i, targetI := 0, rand.Intn(len(megaBikes))
-	// Go doesn't have a sensible way to do this...
-	for id := range megaBikes {
-		if i == targetI {
-			return id
-		}
-		i++
+	if len(megaBikes) == 0 {
+		panic("no bikes")
 	}
-	panic("no bikes")
+	return utils.SortedIDs(megaBikes)[bb.rng.Intn(len(megaBikes))]
 }
 
 func (bb *BaseBiker) SetBike(bikeId uuid.UUID) {
@@ -255,7 +298,7 @@ func (bb *BaseBiker) GetGroupID() int {
 
 // this is called when a lootbox of the desidered colour has been looted in order to update the sought colour
 func (bb *BaseBiker) UpdateColour(totColours utils.Colour) {
-	bb.soughtColour = utils.Colour(rand.Intn(int(totColours)))
+	bb.soughtColour = utils.Colour(bb.rng.Intn(int(totColours)))
 }
 
 // update the points at the end of a round
@@ -292,6 +335,12 @@ func (bb *BaseBiker) GetGameState() IGameState {
 	return bb.gameState
 }
 
+// GetRand returns the random number generator of the simulation. Agents should draw their random
+// decisions from it (rather than from math/rand) so that games can be replayed from their seed.
+func (bb *BaseBiker) GetRand() *rand.Rand {
+	return bb.rng
+}
+
 // Returns the other agents on your bike :)
 func (bb *BaseBiker) GetFellowBikers() []IBaseBiker {
 	bikes := bb.gameState.GetMegaBikes()
@@ -367,7 +416,7 @@ func (bb *BaseBiker) VoteForKickout() map[uuid.UUID]int {
 		agentID := agent.GetID()
 		if agentID != bb.GetID() {
 			// random votes to other agents
-			voteResults[agentID] = rand.Intn(2) // randomly assigns 0 or 1 vote
+			voteResults[agentID] = bb.rng.Intn(2) // randomly assigns 0 or 1 vote
 		}
 	}
 
@@ -407,6 +456,15 @@ func (bb *BaseBiker) VoteLeader() voting.IdVoteMap {
 	return votes
 }
 
+// defaults to voting for the fellow bikers in proportion to their energy
+func (bb *BaseBiker) VoteCouncil() voting.IdVoteMap {
+	votes := make(voting.IdVoteMap)
+	for _, fellowBiker := range bb.GetFellowBikers() {
+		votes[fellowBiker.GetID()] = fellowBiker.GetEnergyLevel()
+	}
+	return votes
+}
+
 // defaults to an equal distribution over all agents for all actions
 func (bb *BaseBiker) DecideWeights(action utils.Action) map[uuid.UUID]float64 {
 	weights := make(map[uuid.UUID]float64)
@@ -422,6 +480,11 @@ func (bb *BaseBiker) DecideKickOut() []uuid.UUID {
 	return (make([]uuid.UUID, 0))
 }
 
+// only called when the agent is the ruler, in the MVP rulers sanction no one
+func (bb *BaseBiker) DecideSanctions() []uuid.UUID {
+	return make([]uuid.UUID, 0)
+}
+
 // only called when the agent is the dictator
 func (bb *BaseBiker) DecideDictatorAllocation() voting.IdVoteMap {
 	bikeID := bb.GetBike()
@@ -490,6 +553,8 @@ func (bb *BaseBiker) CreateLootboxMessage() LootboxMessage {
 	return LootboxMessage{
 		BaseMessage: messaging.CreateMessage[IBaseBiker](bb, bb.GetFellowBikers()),
 		LootboxId:   uuid.Nil,
+		Resources:   utils.HiddenResources,
+		Colour:      utils.HiddenColour,
 	}
 }
 
@@ -633,25 +698,21 @@ func (bb *BaseBiker) HandleVoteKickoutMessage(msg VoteKickoutMessage) {
 }
 
 // this function is going to be called by the server to instantiate bikers in the MVP
-func GetIBaseBiker(totColours utils.Colour, bikeId uuid.UUID) IBaseBiker {
-	return &BaseBiker{
-		BaseAgent:    baseAgent.NewBaseAgent[IBaseBiker](),
-		soughtColour: utils.GenerateRandomColour(),
-		onBike:       true,
-		energyLevel:  1.0,
-		points:       0,
-		GroupID:      0,
-	}
+func GetIBaseBiker(totColours utils.Colour, bikeId uuid.UUID, rng *rand.Rand) IBaseBiker {
+	return GetBaseBiker(totColours, bikeId, rng)
 }
 
 // this function will be used by GetTeamAgent to get the ref to the BaseBiker
-func GetBaseBiker(totColours utils.Colour, bikeId uuid.UUID) *BaseBiker {
+// the biker draws its ID, colour and decisions from rng, the server passes the simulation's random number generator
+func GetBaseBiker(totColours utils.Colour, bikeId uuid.UUID, rng *rand.Rand) *BaseBiker {
 	return &BaseBiker{
 		BaseAgent:    baseAgent.NewBaseAgent[IBaseBiker](),
-		soughtColour: utils.GenerateRandomColour(),
+		id:           utils.NewUUID(rng),
+		soughtColour: utils.GenerateRandomColour(rng),
 		onBike:       true,
 		energyLevel:  1.0,
 		points:       0,
 		GroupID:      0,
+		rng:          rng,
 	}
 }
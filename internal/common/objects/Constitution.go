@@ -0,0 +1,68 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"errors"
+	"fmt"
+)
+
+/*
+A Constitution holds the rules of a bike, which the server enforces. Every bike starts with the rules of the
+simulation config, and its riders can amend them by vote (or its dictator by decree).
+*/
+
+type Constitution struct {
+	KickoutThreshold         float64                `json:"kickout_threshold"`         // a rider is kicked out with more than this share of the votes
+	AdmissionQuorum          float64                `json:"admission_quorum"`          // an agent is let on the bike with more than this share of the votes
+	DirectionVoting          utils.VoteMethod       `json:"direction_voting"`          // how the votes of the riders on the direction are counted
+	LootAllocation           utils.AllocationMethod `json:"loot_allocation"`           // how the allocation votes of the riders are combined
+	GovernanceSupermajority  float64                `json:"governance_supermajority"`  // share of the votes a governance needs to win a referendum
+	RulerTerm                int                    `json:"ruler_term"`                // rounds a ruler serves before a new election, 0 for no elections
+	AmendmentSupermajority   float64                `json:"amendment_supermajority"`   // share of the votes an amendment needs to pass
+	TermLimit                int                    `json:"term_limit"`                // consecutive terms a ruler or council member can serve, 0 for no limit
+	ImpeachmentSupermajority float64                `json:"impeachment_supermajority"` // share of the votes of the other riders needed to impeach the ruler
+	CouncilSize              int                    `json:"council_size"`              // riders elected to the council when the bike is governed by one
+}
+
+// GetConstitution is a constructor for the constitution bikes start with, taken from the simulation config
+func GetConstitution(config utils.SimConfig) Constitution {
+	return Constitution{
+		KickoutThreshold:         config.KickoutThreshold,
+		AdmissionQuorum:          config.AdmissionQuorum,
+		DirectionVoting:          config.VoteAction,
+		LootAllocation:           config.LootAllocation,
+		GovernanceSupermajority:  config.ReferendumSupermajority,
+		RulerTerm:                config.RulerTerm,
+		AmendmentSupermajority:   config.AmendmentSupermajority,
+		TermLimit:                config.TermLimit,
+		ImpeachmentSupermajority: config.ImpeachmentSupermajority,
+		CouncilSize:              config.CouncilSize,
+	}
+}
+
+// Validate returns an error if the rules cannot be enforced, amendments breaking them are rejected
+func (c Constitution) Validate() error {
+	var errs []error
+	if c.KickoutThreshold < 0 || c.KickoutThreshold >= 1 || c.AdmissionQuorum < 0 || c.AdmissionQuorum >= 1 {
+		errs = append(errs, errors.New("the kickout threshold and admission quorum must be between 0 and 1 (excluded)"))
+	}
+	if c.DirectionVoting < utils.PLURALITY || c.DirectionVoting > utils.COPELANDSCORING {
+		errs = append(errs, fmt.Errorf("invalid direction voting method %d", int(c.DirectionVoting)))
+	}
+	if c.LootAllocation < utils.MeanAllocation || c.LootAllocation > utils.EqualAllocation {
+		errs = append(errs, fmt.Errorf("invalid loot allocation method %d", int(c.LootAllocation)))
+	}
+	for _, supermajority := range []float64{c.GovernanceSupermajority, c.AmendmentSupermajority, c.ImpeachmentSupermajority} {
+		if supermajority <= 0 || supermajority > 1 {
+			errs = append(errs, errors.New("supermajorities must be between 0 (excluded) and 1"))
+			break
+		}
+	}
+	if c.RulerTerm < 0 || c.TermLimit < 0 {
+		errs = append(errs, errors.New("the ruler term and term limit cannot be negative"))
+	}
+	if c.CouncilSize < 1 {
+		errs = append(errs, errors.New("the council needs at least one seat"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package objects
+
+import (
+	utils "SOMAS2023/internal/common/utils"
+	"math"
+)
+
+/*
+The IEnergyModel gives the energy agents spend every round. Costs are positive amounts of energy,
+which the server takes off the energy level of the agents.
+*/
+
+type IEnergyModel interface {
+	// energy spent by a rider for the forces it applied on its bike this round
+	RidingCost(forces utils.Forces, bike IMegaBike) float64
+	// energy spent by a rider for taking part in the decisions of its bike this round
+	GovernanceCost(governance utils.Governance) float64
+	// energy spent every round by every agent, on a bike or not
+	BasalCost() float64
+	// energy spent by an agent for a round off a bike
+	LimboCost() float64
+}
+
+// EnergyModel is the energy model built from the simulation config
+type EnergyModel struct {
+	MovingDepletion float64 // cost of pedalling at full force
+	PedalExponent   float64 // shape of the pedalling cost curve, 1 is linear and 2 makes hard pedalling expensive
+	// extra cost per unit of mass each pedalling rider moves, the fewer riders pedal the more it costs them
+	LoadCost        float64
+	BrakingCost     float64 // cost of braking at full force
+	SteeringCost    float64 // cost of steering by 180°
+	BasalMetabolism float64 // cost of staying alive for a round
+
+	DeliberativeDemocracyPenalty float64
+	LeadershipDemocracyPenalty   float64
+	CouncilDemocracyPenalty      float64
+	LimboEnergyPenalty           float64
+}
+
+// GetEnergyModel is a constructor for EnergyModel that takes its costs from the simulation config
+func GetEnergyModel(config utils.SimConfig) EnergyModel {
+	return EnergyModel{
+		MovingDepletion:              config.MovingDepletion,
+		PedalExponent:                config.PedalExponent,
+		LoadCost:                     config.LoadCost,
+		BrakingCost:                  config.BrakingCost,
+		SteeringCost:                 config.SteeringCost,
+		BasalMetabolism:              config.BasalMetabolism,
+		DeliberativeDemocracyPenalty: config.DeliberativeDemocracyPenalty,
+		LeadershipDemocracyPenalty:   config.LeadershipDemocracyPenalty,
+		CouncilDemocracyPenalty:      config.CouncilDemocracyPenalty,
+		LimboEnergyPenalty:           config.LimboEnergyPenalty,
+	}
+}
+
+func (em EnergyModel) RidingCost(forces utils.Forces, bike IMegaBike) float64 {
+	cost := math.Pow(forces.Pedal, em.PedalExponent) * em.MovingDepletion
+	if em.LoadCost != 0 && forces.Pedal > 0 {
+		cost += em.LoadCost * forces.Pedal * em.loadPerPedallingRider(bike)
+	}
+	cost += forces.Brake * em.BrakingCost
+	if forces.Turning.SteerBike {
+		cost += math.Abs(forces.Turning.SteeringForce) * em.SteeringCost
+	}
+	return cost
+}
+
+func (em EnergyModel) GovernanceCost(governance utils.Governance) float64 {
+	switch governance {
+	case utils.Democracy:
+		return em.DeliberativeDemocracyPenalty
+	case utils.Leadership:
+		return em.LeadershipDemocracyPenalty
+	case utils.Council:
+		return em.CouncilDemocracyPenalty
+	default:
+		return 0.0
+	}
+}
+
+func (em EnergyModel) BasalCost() float64 {
+	return em.BasalMetabolism
+}
+
+func (em EnergyModel) LimboCost() float64 {
+	return -em.LimboEnergyPenalty
+}
+
+// loadPerPedallingRider shares the mass of the bike between the riders that are pedalling
+func (em EnergyModel) loadPerPedallingRider(bike IMegaBike) float64 {
+	pedalling := 0
+	for _, agent := range bike.GetAgents() {
+		if agent.GetForces().Pedal > 0 {
+			pedalling++
+		}
+	}
+	if pedalling == 0 {
+		return 0.0
+	}
+	return bike.GetPhysicalState().Mass / float64(pedalling)
+}
@@ -1,6 +1,10 @@
 package objects
 
-import "github.com/google/uuid"
+import (
+	"SOMAS2023/internal/common/utils"
+
+	"github.com/google/uuid"
+)
 
 /*
 IGameState is an interface for GameState that objects will use to get the current game state
@@ -9,5 +13,14 @@ type IGameState interface {
 	GetLootBoxes() map[uuid.UUID]ILootBox
 	GetMegaBikes() map[uuid.UUID]IMegaBike
 	GetAgents() map[uuid.UUID]IBaseBiker
+	// GetAudi returns the first audi, GetAudis returns every audi
 	GetAudi() IAudi
+	GetAudis() map[uuid.UUID]IAudi
+
+	// Proximity queries answered by a spatial index rather than by scanning every object. Distances
+	// follow the boundary of the world and the IDs are sorted from nearest to furthest.
+	GetLootBoxesWithin(position utils.Coordinates, radius float64) []uuid.UUID
+	GetMegaBikesWithin(position utils.Coordinates, radius float64) []uuid.UUID
+	GetNearestLootBoxes(position utils.Coordinates, k int) []uuid.UUID
+	GetNearestLootBoxesOfColour(position utils.Coordinates, k int, colour utils.Colour) []uuid.UUID
 }
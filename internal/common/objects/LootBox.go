@@ -2,27 +2,57 @@ package objects
 
 import (
 	utils "SOMAS2023/internal/common/utils"
+	"math/rand"
 )
 
 type ILootBox interface {
 	IPhysicsObject
 	GetTotalResources() float64
 	GetColour() utils.Colour
+	// returns the number of rounds before the lootbox expires, or -1 if it never expires
+	GetTimeToLive() int
+	// called by the server at the end of every round, the lootbox loses some of its resources and gets closer to expiring
+	Age()
 }
 
 type LootBox struct {
 	*PhysicsObject
 	colour    utils.Colour
 	totalLoot float64
+	// lifecycle, see Age
+	decay      float64
+	timeToLive int
+	driftForce float64
 }
 
 // GetLootBox is a constructor for LootBox that initializes it with a new UUID and default position.
-func GetLootBox() *LootBox {
-	return &LootBox{
-		PhysicsObject: GetPhysicsObject(0),
-		colour:        utils.GenerateRandomColour(),    // Initialize to randomized colour
-		totalLoot:     utils.GenerateRandomFloat(5, 8), // Initialize to randomized totalLoot
+// It is placed and filled by a new spawn policy built from the config, use SpawnLootBox to keep the
+// hotspots and rich regions of a policy from one lootbox to the next.
+func GetLootBox(config utils.SimConfig, rng *rand.Rand) *LootBox {
+	return SpawnLootBox(config, rng, GetLootBoxSpawnPolicy(config, rng))
+}
+
+// SpawnLootBox is a constructor for LootBox that initializes it with a new UUID, and the position,
+// colour and resources given by the spawn policy
+func SpawnLootBox(config utils.SimConfig, rng *rand.Rand, policy ILootBoxSpawnPolicy) *LootBox {
+	id := utils.NewUUID(rng)
+	position := policy.Position()
+	lootBox := &LootBox{
+		PhysicsObject: getPhysicsObjectAt(id, position, config.MassLootBox, config),
+		colour:        policy.Colour(position),
+		totalLoot:     policy.Resources(position),
+		decay:         config.LootBoxDecay,
+		timeToLive:    -1,
+		driftForce:    config.LootBoxDriftForce,
+	}
+	if config.LootBoxLifetime > 0 {
+		lootBox.timeToLive = config.LootBoxLifetime
 	}
+	if lootBox.driftForce > 0 {
+		// drifting lootboxes head in a random direction
+		lootBox.orientation = utils.GenerateRandomFloat(rng, -1, 1)
+	}
+	return lootBox
 }
 
 // returns the total loot of the object
@@ -34,3 +64,20 @@ func (lb *LootBox) GetTotalResources() float64 {
 func (lb *LootBox) GetColour() utils.Colour {
 	return lb.colour
 }
+
+func (lb *LootBox) GetTimeToLive() int {
+	return lb.timeToLive
+}
+
+// Age takes the decay off the resources of the lootbox and counts down its time to live
+func (lb *LootBox) Age() {
+	lb.totalLoot *= 1 - lb.decay
+	if lb.timeToLive > 0 {
+		lb.timeToLive--
+	}
+}
+
+// Drifting lootboxes are pushed along their orientation, the others stay where they spawned
+func (lb *LootBox) UpdateForce() {
+	lb.force = lb.driftForce
+}
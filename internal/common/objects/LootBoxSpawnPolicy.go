@@ -0,0 +1,122 @@
+package objects
+
+import (
+	phy "SOMAS2023/internal/common/physics"
+	"SOMAS2023/internal/common/utils"
+	"math"
+	"math/rand"
+)
+
+/*
+An ILootBoxSpawnPolicy decides where lootboxes appear, what they hold and when they are replenished, so
+that abundant and scarce environments can be simulated. The server keeps one policy for the whole run.
+*/
+
+type ILootBoxSpawnPolicy interface {
+	// returns the position of a new lootbox
+	Position() utils.Coordinates
+	// returns the colour of a new lootbox at the given position
+	Colour(position utils.Coordinates) utils.Colour
+	// returns the resources of a new lootbox at the given position
+	Resources(position utils.Coordinates) float64
+	// returns whether the lootboxes that have been looted are replaced at the end of the given round
+	Replenishes(round int) bool
+}
+
+// LootBoxSpawnPolicy is the spawn policy built from the simulation config
+type LootBoxSpawnPolicy struct {
+	rng      *rand.Rand
+	boundary phy.Boundary
+
+	placement     utils.LootBoxPlacementMode
+	hotspots      []utils.Coordinates
+	hotspotSpread float64
+
+	colouring utils.LootBoxColourMode
+
+	distribution   utils.ResourceDistribution
+	minResources   float64
+	maxResources   float64
+	richRegions    []utils.Coordinates
+	richRadius     float64
+	richMultiplier float64
+
+	// replenishment pauses for the last scarcityRounds rounds of every scarcityPeriod rounds
+	scarcityPeriod int
+	scarcityRounds int
+}
+
+// GetLootBoxSpawnPolicy is a constructor for LootBoxSpawnPolicy that takes its parameters from the simulation
+// config. The hotspots and rich regions are placed at random on the grid when the policy is created.
+func GetLootBoxSpawnPolicy(config utils.SimConfig, rng *rand.Rand) *LootBoxSpawnPolicy {
+	policy := &LootBoxSpawnPolicy{
+		rng:            rng,
+		boundary:       phy.NewBoundary(config),
+		placement:      config.LootBoxPlacement,
+		hotspotSpread:  config.LootBoxHotspotSpread,
+		colouring:      config.LootBoxColouring,
+		distribution:   config.LootBoxResources,
+		minResources:   config.LootBoxMinResources,
+		maxResources:   config.LootBoxMaxResources,
+		richRadius:     config.LootBoxRichRadius,
+		richMultiplier: config.LootBoxRichMultiplier,
+		scarcityPeriod: config.LootBoxScarcityPeriod,
+		scarcityRounds: config.LootBoxScarcityRounds,
+	}
+	if policy.placement == utils.HotspotPlacement {
+		for i := 0; i < config.LootBoxHotspots; i++ {
+			policy.hotspots = append(policy.hotspots, utils.GenerateRandomCoordinates(rng, config.GridWidth, config.GridHeight))
+		}
+	}
+	for i := 0; i < config.LootBoxRichRegions; i++ {
+		policy.richRegions = append(policy.richRegions, utils.GenerateRandomCoordinates(rng, config.GridWidth, config.GridHeight))
+	}
+	return policy
+}
+
+func (lp *LootBoxSpawnPolicy) Position() utils.Coordinates {
+	if lp.placement != utils.HotspotPlacement {
+		return utils.GenerateRandomCoordinates(lp.rng, lp.boundary.Width, lp.boundary.Height)
+	}
+	// scatter the lootbox around one of the hotspots, keeping it on the grid
+	hotspot := lp.hotspots[lp.rng.Intn(len(lp.hotspots))]
+	return utils.Coordinates{
+		X: math.Max(0, math.Min(hotspot.X+lp.rng.NormFloat64()*lp.hotspotSpread, lp.boundary.Width)),
+		Y: math.Max(0, math.Min(hotspot.Y+lp.rng.NormFloat64()*lp.hotspotSpread, lp.boundary.Height)),
+	}
+}
+
+func (lp *LootBoxSpawnPolicy) Colour(position utils.Coordinates) utils.Colour {
+	if lp.colouring != utils.ZonedColours {
+		return utils.GenerateRandomColour(lp.rng)
+	}
+	zone := int(position.X / lp.boundary.Width * float64(utils.NumOfColours))
+	return utils.Colour(max(0, min(zone, int(utils.NumOfColours)-1)))
+}
+
+func (lp *LootBoxSpawnPolicy) Resources(position utils.Coordinates) float64 {
+	var resources float64
+	middle := (lp.minResources + lp.maxResources) / 2
+	switch lp.distribution {
+	case utils.NormalResources:
+		resources = middle + lp.rng.NormFloat64()*(lp.maxResources-lp.minResources)/4
+		resources = math.Max(lp.minResources, math.Min(resources, lp.maxResources))
+	case utils.ExponentialResources:
+		resources = lp.minResources + lp.rng.ExpFloat64()*(middle-lp.minResources)
+	default:
+		resources = utils.GenerateRandomFloat(lp.rng, lp.minResources, lp.maxResources)
+	}
+	for _, region := range lp.richRegions {
+		if lp.boundary.ComputeDistance(position, region) < lp.richRadius*lp.richRadius {
+			return resources * lp.richMultiplier
+		}
+	}
+	return resources
+}
+
+func (lp *LootBoxSpawnPolicy) Replenishes(round int) bool {
+	if lp.scarcityPeriod == 0 {
+		return true
+	}
+	return round%lp.scarcityPeriod < lp.scarcityPeriod-lp.scarcityRounds
+}
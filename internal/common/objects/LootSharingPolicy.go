@@ -0,0 +1,165 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+/*
+An ILootSharingPolicy decides how a lootbox is shared between the bikes that reached it during the same step.
+The server gives each bike its share of the resources, which the riders then allocate between themselves.
+*/
+
+type ILootSharingPolicy interface {
+	// returns the fraction of the lootbox each bike gets, bikes that are left out get nothing. Contestants are
+	// sorted by time of impact, then by ID.
+	Share(lootBox ILootBox, contestants []LootContestant) map[uuid.UUID]float64
+}
+
+// LootContestant is a bike that reached a lootbox during the last step
+type LootContestant struct {
+	Bike         IMegaBike
+	TimeOfImpact float64 // fraction of the step at which the bike reached the lootbox
+}
+
+// GetLootSharingPolicy is a constructor for the sharing policies that can be chosen in the simulation config
+func GetLootSharingPolicy(config utils.SimConfig) ILootSharingPolicy {
+	switch config.LootSharing {
+	case utils.FirstArrival:
+		return &FirstArrivalPolicy{}
+	case utils.RiderShare:
+		return &RiderSharePolicy{}
+	case utils.EffortShare:
+		return &EffortSharePolicy{}
+	case utils.Bargaining:
+		return &BargainingPolicy{}
+	default:
+		return &EqualSplitPolicy{}
+	}
+}
+
+// EqualSplitPolicy splits the lootbox equally between every bike that reached it, whenever it arrived
+type EqualSplitPolicy struct{}
+
+func (ep *EqualSplitPolicy) Share(lootBox ILootBox, contestants []LootContestant) map[uuid.UUID]float64 {
+	shares := make(map[uuid.UUID]float64, len(contestants))
+	for _, contestant := range contestants {
+		shares[contestant.Bike.GetID()] = 1.0 / float64(len(contestants))
+	}
+	return shares
+}
+
+// FirstArrivalPolicy gives the whole lootbox to the bike that reached it first, ties going to the bike with the lowest ID
+type FirstArrivalPolicy struct{}
+
+func (fp *FirstArrivalPolicy) Share(lootBox ILootBox, contestants []LootContestant) map[uuid.UUID]float64 {
+	if len(contestants) == 0 {
+		return map[uuid.UUID]float64{}
+	}
+	return map[uuid.UUID]float64{contestants[0].Bike.GetID(): 1.0}
+}
+
+// RiderSharePolicy splits the lootbox between every bike that reached it, in proportion to their number of riders
+type RiderSharePolicy struct{}
+
+func (rp *RiderSharePolicy) Share(lootBox ILootBox, contestants []LootContestant) map[uuid.UUID]float64 {
+	return proportionalShares(contestants, func(bike IMegaBike) float64 {
+		return float64(len(bike.GetAgents()))
+	})
+}
+
+// EffortSharePolicy splits the lootbox between every bike that reached it, in proportion to how hard their riders pedalled
+type EffortSharePolicy struct{}
+
+func (ep *EffortSharePolicy) Share(lootBox ILootBox, contestants []LootContestant) map[uuid.UUID]float64 {
+	return proportionalShares(contestants, func(bike IMegaBike) float64 {
+		effort := 0.0
+		for _, agent := range bike.GetAgents() {
+			effort += agent.GetForces().Pedal
+		}
+		return effort
+	})
+}
+
+// BargainingPolicy has the bikes that reached the lootbox claim a fraction of it. If the claims add up to at most the
+// whole lootbox every bike gets its claim (and what is left is wasted), otherwise the bargaining fails and no one gets
+// anything. The ruler makes the claim of a bike it leads, the riders of a democracy claim the mean of their claims.
+type BargainingPolicy struct{}
+
+func (bp *BargainingPolicy) Share(lootBox ILootBox, contestants []LootContestant) map[uuid.UUID]float64 {
+	claims := make(map[uuid.UUID]float64, len(contestants))
+	total := 0.0
+	for _, contestant := range contestants {
+		bike := contestant.Bike
+		rivals := make([]uuid.UUID, 0, len(contestants)-1)
+		for _, other := range contestants {
+			if other.Bike.GetID() != bike.GetID() {
+				rivals = append(rivals, other.Bike.GetID())
+			}
+		}
+		claim := bikeClaim(bike, lootBox.GetID(), rivals)
+		claims[bike.GetID()] = claim
+		total += claim
+	}
+	if total > 1.0+utils.Epsilon {
+		return map[uuid.UUID]float64{}
+	}
+	return claims
+}
+
+// bikeClaim returns the fraction of the lootbox a bike claims, between 0 and 1
+func bikeClaim(bike IMegaBike, lootBox uuid.UUID, rivals []uuid.UUID) float64 {
+	agents := bike.GetAgents()
+	if len(agents) == 0 {
+		return 0.0
+	}
+	claim := 0.0
+	governance := bike.GetGovernance()
+	if governance == utils.Leadership || governance == utils.Dictatorship {
+		for _, agent := range agents {
+			if agent.GetID() == bike.GetRuler() {
+				return clampClaim(agent.DecideLootClaim(lootBox, rivals))
+			}
+		}
+	}
+	// the claim of a council is the average claim of its members
+	if governance == utils.Council && len(bike.GetCouncil()) > 0 {
+		members := 0
+		for _, agent := range agents {
+			if slices.Contains(bike.GetCouncil(), agent.GetID()) {
+				claim += clampClaim(agent.DecideLootClaim(lootBox, rivals))
+				members++
+			}
+		}
+		if members > 0 {
+			return claim / float64(members)
+		}
+	}
+	for _, agent := range agents {
+		claim += clampClaim(agent.DecideLootClaim(lootBox, rivals))
+	}
+	return claim / float64(len(agents))
+}
+
+func clampClaim(claim float64) float64 {
+	return max(0.0, min(claim, 1.0))
+}
+
+// proportionalShares shares the lootbox in proportion to the weight of each bike, or equally if no bike has any weight
+func proportionalShares(contestants []LootContestant, weight func(IMegaBike) float64) map[uuid.UUID]float64 {
+	shares := make(map[uuid.UUID]float64, len(contestants))
+	total := 0.0
+	for _, contestant := range contestants {
+		total += weight(contestant.Bike)
+	}
+	for _, contestant := range contestants {
+		if total > 0 {
+			shares[contestant.Bike.GetID()] = weight(contestant.Bike) / total
+		} else {
+			shares[contestant.Bike.GetID()] = 1.0 / float64(len(contestants))
+		}
+	}
+	return shares
+}
@@ -2,6 +2,9 @@ package objects
 
 import (
 	utils "SOMAS2023/internal/common/utils"
+	"math"
+	"math/rand"
+	"slices"
 
 	"github.com/google/uuid"
 )
@@ -17,6 +20,21 @@ type IMegaBike interface {
 	GetRuler() uuid.UUID
 	SetGovernance(governance utils.Governance)
 	SetRuler(ruler uuid.UUID)
+	GetMaxTurn() float64
+	GetShield() float64
+	AddShield(energy float64)
+	GetFinePool() float64
+	AddFinePool(energy float64)
+	GetConstitution() Constitution
+	SetConstitution(constitution Constitution)
+	GetRulerTenure() int
+	IncrementRulerTenure()
+	GetRulerTerms() int
+	GetCouncil() []uuid.UUID
+	SetCouncil(council []uuid.UUID)
+	FillCouncil(council []uuid.UUID)
+	GetCouncilTerms(member uuid.UUID) int
+	CouncilKickOut(weights map[uuid.UUID]float64) []uuid.UUID
 }
 
 // MegaBike will have the following forces
@@ -26,14 +44,34 @@ type MegaBike struct {
 	kickedOutCount int
 	governance     utils.Governance
 	ruler          uuid.UUID
+	rulerTenure    int               // rounds the ruler has served since its election
+	rulerTerms     int               // consecutive terms the ruler has been elected for, including the current one
+	council        []uuid.UUID       // riders ruling the bike jointly when it is governed by a council
+	councilTerms   map[uuid.UUID]int // consecutive terms each council member has been elected for
+	constitution   Constitution      // rules of the bike, enforced by the server
+	shield         float64           // energy pooled by the riders to protect the bike from the audi
+	finePool       float64           // energy paid in fines by sanctioned riders, shared out with the next loot
+	massBike       float64
+	massBiker      float64
+	// turning dynamics, see UpdateOrientation
+	maxTurnRate      float64
+	turnInertia      float64
+	turnMomentumLoss float64
 }
 
 // GetMegaBike is a constructor for MegaBike that initializes it with a new UUID and default position.
-func GetMegaBike() *MegaBike {
+func GetMegaBike(config utils.SimConfig, rng *rand.Rand) *MegaBike {
 	return &MegaBike{
-		PhysicsObject: GetPhysicsObject(utils.MassBike),
+		PhysicsObject: GetPhysicsObject(config.MassBike, config, rng),
 		governance:    utils.Democracy,
 		ruler:         uuid.Nil,
+		constitution:  GetConstitution(config),
+		massBike:      config.MassBike,
+		massBiker:     config.MassBiker,
+
+		maxTurnRate:      config.MaxTurnRate,
+		turnInertia:      config.TurnInertia,
+		turnMomentumLoss: config.TurnMomentumLoss,
 	}
 }
 
@@ -64,31 +102,24 @@ func (mb *MegaBike) GetAgents() []IBaseBiker {
 
 // Calculate the mass of the bike with all it's agents
 func (mb *MegaBike) UpdateMass() {
-	mass := utils.MassBike
-	mass += float64(len(mb.agents))
+	mass := mb.massBike
+	mass += float64(len(mb.agents)) * mb.massBiker
 	mb.mass = mass
 }
 
-// Calculates and returns the total force of the Megabike based on the Biker's force
+// Calculates and returns the total force of the Megabike, each Biker contributes its pedal force minus its brake force
 func (mb *MegaBike) UpdateForce() {
-	if len(mb.agents) == 0 {
-		mb.force = 0.0
-	}
-	totalPedal := 0.0
-	totalBrake := 0.0
+	totalForce := 0.0
 	for _, agent := range mb.agents {
 		force := agent.GetForces()
-
-		if force.Pedal != 0 {
-			totalPedal += float64(force.Pedal)
-		} else {
-			totalBrake += float64(force.Brake)
-		}
+		totalForce += force.Pedal - force.Brake
 	}
-	mb.force = (float64(totalPedal) - float64(totalBrake))
+	mb.force = totalForce
 }
 
-// Calculates the final orientation of the Megabike, between -1 and 1 (-180° to 180°), given the Biker's Turning forces
+// Calculates the final orientation of the Megabike, between -1 and 1 (-180° to 180°), given the Biker's Turning forces.
+// The bike turns by the average steering of the Bikers, up to the maximum turn it can make at its current momentum,
+// and loses some of its velocity in the turn.
 func (mb *MegaBike) UpdateOrientation() {
 	totalTurning := 0.0
 	numOfSteeringAgents := 0
@@ -104,7 +135,10 @@ func (mb *MegaBike) UpdateOrientation() {
 	// Do not update orientation if no biker want to steer
 	if numOfSteeringAgents > 0 {
 		averageTurning := totalTurning / float64(numOfSteeringAgents)
-		mb.orientation += (averageTurning)
+		maxTurn := mb.GetMaxTurn()
+		turn := math.Max(-maxTurn, math.Min(averageTurning, maxTurn))
+		mb.orientation += turn
+		mb.velocity *= math.Max(0, 1-mb.turnMomentumLoss*math.Abs(turn))
 	}
 	// ensure the orientation wraps around if it exceeds the range 1.0 or -1.0
 
@@ -115,6 +149,12 @@ func (mb *MegaBike) UpdateOrientation() {
 	}
 }
 
+// GetMaxTurn returns the largest turn (in the units of the orientation) the bike can make this round. Heavier
+// and faster bikes have more momentum and cannot turn as sharply.
+func (mb *MegaBike) GetMaxTurn() float64 {
+	return mb.maxTurnRate / (1 + mb.turnInertia*mb.mass*mb.velocity)
+}
+
 // get the count of kicked out agents
 func (mb *MegaBike) GetKickedOutCount() int {
 	return mb.kickedOutCount
@@ -123,11 +163,14 @@ func (mb *MegaBike) GetKickedOutCount() int {
 // only called for level 0 and level 1
 func (mb *MegaBike) KickOutAgent(weights map[uuid.UUID]float64) []uuid.UUID {
 	voteCount := make(map[uuid.UUID]float64)
+	totalWeight := 0.0
 	// Count votes for each agent
 	for _, agent := range mb.agents {
+		totalWeight += weights[agent.GetID()]
 		agentVotes := agent.VoteForKickout() // Assuming this now returns map[uuid.UUID]int
 		for agentID, votes := range agentVotes {
-			agentWeight := weights[agentID]
+			// the votes of every rider count as much as its weight
+			agentWeight := weights[agent.GetID()]
 			if val, ok := voteCount[agentID]; ok {
 				voteCount[agentID] = float64(val) + agentWeight*float64(votes)
 			} else {
@@ -136,10 +179,11 @@ func (mb *MegaBike) KickOutAgent(weights map[uuid.UUID]float64) []uuid.UUID {
 		}
 	}
 
-	// Find all agents with votes > half the number of agents
+	// Find all agents with votes > the kickout threshold of the constitution (half the total weight by default)
 	agentsToKickOut := make([]uuid.UUID, 0)
-	for agentID, votes := range voteCount {
-		if votes > float64(len(mb.agents))/2.0 {
+	for _, agentID := range utils.SortedIDs(voteCount) {
+		votes := voteCount[agentID]
+		if votes > totalWeight*mb.constitution.KickoutThreshold {
 			agentsToKickOut = append(agentsToKickOut, agentID)
 		}
 	}
@@ -161,6 +205,115 @@ func (mb *MegaBike) SetGovernance(governance utils.Governance) {
 	mb.governance = governance
 }
 
+// SetRuler starts the term of a new ruler, or a new term of the same ruler when it is re-elected
 func (mb *MegaBike) SetRuler(ruler uuid.UUID) {
+	if ruler != mb.ruler {
+		mb.rulerTerms = 0
+	}
+	if ruler != uuid.Nil {
+		mb.rulerTerms++
+	}
 	mb.ruler = ruler
+	mb.rulerTenure = 0
+}
+
+// GetRulerTerms returns the number of consecutive terms the ruler has been elected for
+func (mb *MegaBike) GetRulerTerms() int {
+	return mb.rulerTerms
+}
+
+// GetRulerTenure returns the number of rounds the ruler has served in its current term
+func (mb *MegaBike) GetRulerTenure() int {
+	return mb.rulerTenure
+}
+
+func (mb *MegaBike) IncrementRulerTenure() {
+	mb.rulerTenure++
+}
+
+// GetCouncil returns the members of the council of the bike, in the order they were elected
+func (mb *MegaBike) GetCouncil() []uuid.UUID {
+	return mb.council
+}
+
+// SetCouncil starts the term of a newly elected council, the council shares the tenure of a ruler
+func (mb *MegaBike) SetCouncil(council []uuid.UUID) {
+	terms := make(map[uuid.UUID]int, len(council))
+	for _, member := range council {
+		terms[member] = mb.councilTerms[member] + 1
+	}
+	mb.council = council
+	mb.councilTerms = terms
+	mb.rulerTenure = 0
+}
+
+// FillCouncil seats the members elected to the vacant seats of the council, without starting a new term: the
+// sitting members keep their terms and the new members start their first
+func (mb *MegaBike) FillCouncil(council []uuid.UUID) {
+	terms := make(map[uuid.UUID]int, len(council))
+	for _, member := range council {
+		terms[member] = max(mb.councilTerms[member], 1)
+	}
+	mb.council = council
+	mb.councilTerms = terms
+}
+
+// GetCouncilTerms returns the number of consecutive terms a council member has been elected for
+func (mb *MegaBike) GetCouncilTerms(member uuid.UUID) int {
+	return mb.councilTerms[member]
+}
+
+// CouncilKickOut kicks out the agents the council votes out, with more than the kickout threshold of the
+// constitution of the total weight of the council members
+func (mb *MegaBike) CouncilKickOut(weights map[uuid.UUID]float64) []uuid.UUID {
+	voteCount := make(map[uuid.UUID]float64)
+	totalWeight := 0.0
+	for _, agent := range mb.agents {
+		if !slices.Contains(mb.council, agent.GetID()) {
+			continue
+		}
+		totalWeight += weights[agent.GetID()]
+		for agentID, votes := range agent.VoteForKickout() {
+			voteCount[agentID] += weights[agent.GetID()] * float64(votes)
+		}
+	}
+
+	agentsToKickOut := make([]uuid.UUID, 0)
+	for _, agentID := range utils.SortedIDs(voteCount) {
+		if voteCount[agentID] > totalWeight*mb.constitution.KickoutThreshold {
+			agentsToKickOut = append(agentsToKickOut, agentID)
+		}
+	}
+
+	mb.kickedOutCount += len(agentsToKickOut)
+
+	return agentsToKickOut
+}
+
+func (mb *MegaBike) GetConstitution() Constitution {
+	return mb.constitution
+}
+
+func (mb *MegaBike) SetConstitution(constitution Constitution) {
+	mb.constitution = constitution
+}
+
+// GetShield returns the energy pooled in the shield of the bike
+func (mb *MegaBike) GetShield() float64 {
+	return mb.shield
+}
+
+// AddShield adds energy to the shield of the bike, a negative amount takes energy off it
+func (mb *MegaBike) AddShield(energy float64) {
+	mb.shield = math.Max(0, mb.shield+energy)
+}
+
+// GetFinePool returns the energy paid in fines on the bike that has not been shared out yet
+func (mb *MegaBike) GetFinePool() float64 {
+	return mb.finePool
+}
+
+// AddFinePool adds energy to the fine pool of the bike, a negative amount takes energy out of it
+func (mb *MegaBike) AddFinePool(energy float64) {
+	mb.finePool = math.Max(0, mb.finePool+energy)
 }
@@ -33,7 +33,9 @@ type JoiningAgentMessage struct {
 // "I want to go to this lootbox next iteration"
 type LootboxMessage struct {
 	messaging.BaseMessage[IBaseBiker]
-	LootboxId uuid.UUID // the lootbox that agent wants
+	LootboxId uuid.UUID    // the lootbox that agent wants
+	Resources float64      // the resources the agent saw in the lootbox, utils.HiddenResources if it could not see them
+	Colour    utils.Colour // the colour the agent saw, utils.HiddenColour if it could not see it
 }
 
 // "I would like to operate under this governance system" NOTE: NOT VOTING TO CHANGE GOVERNMENT
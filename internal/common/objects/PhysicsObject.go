@@ -8,9 +8,10 @@ The Bikers/Agents will not need to implement this interface
 */
 
 import (
+	phy "SOMAS2023/internal/common/physics"
 	utils "SOMAS2023/internal/common/utils"
 
-	"math"
+	"math/rand"
 
 	"github.com/google/uuid"
 )
@@ -20,6 +21,8 @@ type IPhysicsObject interface {
 	GetID() uuid.UUID
 	// returns the current coordinates of the object
 	GetPosition() utils.Coordinates
+	// returns the coordinates of the object at the start of its last step
+	GetPreviousPosition() utils.Coordinates
 	GetVelocity() float64
 	GetOrientation() float64
 	GetForce() float64
@@ -27,6 +30,8 @@ type IPhysicsObject interface {
 
 	// Server must set these variables since it updates the gamestate
 	SetPhysicalState(state utils.PhysicalState)
+	// Server sets the orientation when an object bounces off the edge of the grid
+	SetOrientation(orientation float64)
 
 	// This method will update the force of the PhysicsObject based on the current GameState.
 	// I.e. for MegaBike, force will be cacluated from the bikers
@@ -36,16 +41,24 @@ type IPhysicsObject interface {
 	// based on the current GameState
 	UpdateOrientation()
 	CheckForCollision(otherObject IPhysicsObject) bool
+	// returns when, as a fraction of the last step, the objects first collided and whether they did
+	TimeOfImpact(otherObject IPhysicsObject) (float64, bool)
 }
 
 type PhysicsObject struct {
-	id           uuid.UUID
-	coordinates  utils.Coordinates
-	mass         float64
-	acceleration float64
-	velocity     float64
-	orientation  float64
-	force        float64
+	id          uuid.UUID
+	coordinates utils.Coordinates
+	// coordinates at the start of the last step, collisions are checked along the path in between
+	previousCoordinates utils.Coordinates
+	mass                float64
+	acceleration        float64
+	velocity            float64
+	orientation         float64
+	force               float64
+	// distance under which another object is considered to have collided with this one
+	collisionThreshold float64
+	// edges of the grid, distances are measured across them on a torus
+	boundary phy.Boundary
 }
 
 // returns the unique ID of the object
@@ -58,6 +71,10 @@ func (po *PhysicsObject) GetPosition() utils.Coordinates {
 	return po.coordinates
 }
 
+func (po *PhysicsObject) GetPreviousPosition() utils.Coordinates {
+	return po.previousCoordinates
+}
+
 func (po *PhysicsObject) GetVelocity() float64 {
 	return po.velocity
 }
@@ -81,33 +98,48 @@ func (po *PhysicsObject) GetPhysicalState() utils.PhysicalState {
 
 func (po *PhysicsObject) SetPhysicalState(state utils.PhysicalState) {
 	po.mass = state.Mass
+	po.previousCoordinates = po.coordinates
 	po.coordinates = state.Position
 	po.acceleration = state.Acceleration
 	po.velocity = state.Velocity
 }
 
+func (po *PhysicsObject) SetOrientation(orientation float64) {
+	po.orientation = orientation
+}
+
 // this will be used to check if a MegaBike has looted a LootBok or if the Audi has collided with a MegaBike
+// the objects are swept along their last step, so fast objects cannot pass through each other
 func (po *PhysicsObject) CheckForCollision(otherObject IPhysicsObject) bool {
-	otherPos := otherObject.GetPosition()
-	distance := math.Sqrt(math.Pow(otherPos.X-po.coordinates.X, 2) + math.Pow(otherPos.Y-po.coordinates.Y, 2))
-	if distance < utils.CollisionThreshold {
-		return true
-	} else {
-		return false
-	}
+	_, collided := po.TimeOfImpact(otherObject)
+	return collided
+}
+
+func (po *PhysicsObject) TimeOfImpact(otherObject IPhysicsObject) (float64, bool) {
+	return po.boundary.TimeOfImpact(po.previousCoordinates, po.coordinates, otherObject.GetPreviousPosition(), otherObject.GetPosition(), po.collisionThreshold)
 }
 
 func (po *PhysicsObject) UpdateForce() {}
 
 func (po *PhysicsObject) UpdateOrientation() {}
 
-func GetPhysicsObject(mass float64) *PhysicsObject {
+func GetPhysicsObject(mass float64, config utils.SimConfig, rng *rand.Rand) *PhysicsObject {
+	id := utils.NewUUID(rng)
+	coordinates := utils.GenerateRandomCoordinates(rng, config.GridWidth, config.GridHeight)
+	return getPhysicsObjectAt(id, coordinates, mass, config)
+}
+
+// getPhysicsObjectAt creates a stationary physics object at the given coordinates
+func getPhysicsObjectAt(id uuid.UUID, coordinates utils.Coordinates, mass float64, config utils.SimConfig) *PhysicsObject {
 	return &PhysicsObject{
-		id:           uuid.New(),
-		coordinates:  utils.GenerateRandomCoordinates(),
-		mass:         mass,
-		acceleration: 0.0,
-		velocity:     0.0,
-		orientation:  0.0,
+		id:                  id,
+		coordinates:         coordinates,
+		previousCoordinates: coordinates,
+		mass:                mass,
+		acceleration:        0.0,
+		velocity:            0.0,
+		orientation:         0.0,
+		collisionThreshold:  config.CollisionThreshold,
+		boundary:            phy.NewBoundary(config),
 	}
 }
@@ -0,0 +1,119 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConstitution(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.VoteAction = utils.BORDACOUNT
+	config.RulerTerm = 5
+	constitution := objects.GetConstitution(config)
+	assert.Equal(t, config.KickoutThreshold, constitution.KickoutThreshold)
+	assert.Equal(t, config.AdmissionQuorum, constitution.AdmissionQuorum)
+	assert.Equal(t, utils.BORDACOUNT, constitution.DirectionVoting)
+	assert.Equal(t, config.LootAllocation, constitution.LootAllocation)
+	assert.Equal(t, config.ReferendumSupermajority, constitution.GovernanceSupermajority)
+	assert.Equal(t, 5, constitution.RulerTerm)
+	assert.NoError(t, constitution.Validate())
+	assert.Equal(t, constitution, objects.GetMegaBike(config, utils.NewRand(1)).GetConstitution(), "bikes should start with the constitution of the config")
+
+	invalid := constitution
+	invalid.KickoutThreshold = 1
+	assert.Error(t, invalid.Validate())
+	invalid = constitution
+	invalid.LootAllocation = utils.AllocationMethod(10)
+	assert.Error(t, invalid.Validate())
+	invalid = constitution
+	invalid.RulerTerm = -1
+	assert.Error(t, invalid.Validate())
+}
+
+func TestKickOutThreshold(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	bikers := []*MockBiker{NewMockBiker(), NewMockBiker(), NewMockBiker(), NewMockBiker()}
+	weights := make(map[uuid.UUID]float64)
+	for _, biker := range bikers {
+		mb.AddAgent(biker)
+		weights[biker.GetID()] = 1.0
+	}
+	// half the riders want the last one out
+	bikers[0].VoteMap[bikers[3].GetID()] = 1
+	bikers[1].VoteMap[bikers[3].GetID()] = 1
+
+	assert.Empty(t, mb.KickOutAgent(weights), "half the votes should not be enough by default")
+	constitution := mb.GetConstitution()
+	constitution.KickoutThreshold = 0.4
+	mb.SetConstitution(constitution)
+	assert.Equal(t, []uuid.UUID{bikers[3].GetID()}, mb.KickOutAgent(weights))
+}
+
+func TestWeightedKickOutThreshold(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	bikers := []*MockBiker{NewMockBiker(), NewMockBiker(), NewMockBiker(), NewMockBiker()}
+	weights := make(map[uuid.UUID]float64)
+	for _, biker := range bikers {
+		mb.AddAgent(biker)
+		weights[biker.GetID()] = 0.5
+	}
+	weights[bikers[0].GetID()] = 1.0
+	bikers[0].VoteMap[bikers[3].GetID()] = 1
+	bikers[1].VoteMap[bikers[3].GetID()] = 1
+
+	// 1.5 out of a total weight of 2.5 is more than half, although it is less than half the number of riders
+	assert.Equal(t, []uuid.UUID{bikers[3].GetID()}, mb.KickOutAgent(weights), "the threshold should be a fraction of the total weight")
+}
+
+func TestRulerTenure(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	ruler := uuid.New()
+	mb.SetRuler(ruler)
+	mb.IncrementRulerTenure()
+	mb.IncrementRulerTenure()
+	assert.Equal(t, 2, mb.GetRulerTenure())
+	mb.SetRuler(ruler)
+	assert.Equal(t, 0, mb.GetRulerTenure(), "a re-elected ruler should start a new term")
+}
+
+func TestRulerTerms(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	ruler := uuid.New()
+	mb.SetRuler(ruler)
+	mb.SetRuler(ruler)
+	assert.Equal(t, 2, mb.GetRulerTerms())
+	mb.SetRuler(uuid.New())
+	assert.Equal(t, 1, mb.GetRulerTerms(), "a new ruler should start counting its terms")
+	mb.SetRuler(uuid.Nil)
+	assert.Equal(t, 0, mb.GetRulerTerms())
+}
+
+func TestCouncilTerms(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	mb.SetCouncil([]uuid.UUID{first, second})
+	mb.SetCouncil([]uuid.UUID{first, third})
+	assert.Equal(t, 2, mb.GetCouncilTerms(first))
+	assert.Equal(t, 0, mb.GetCouncilTerms(second), "a member who lost its seat should stop counting its terms")
+	assert.Equal(t, 1, mb.GetCouncilTerms(third))
+	mb.SetCouncil(nil)
+	assert.Equal(t, 0, mb.GetCouncilTerms(first))
+}
+
+func TestFillCouncil(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	mb.SetCouncil([]uuid.UUID{first, second})
+	mb.SetCouncil([]uuid.UUID{first, second})
+	mb.IncrementRulerTenure()
+	mb.FillCouncil([]uuid.UUID{first, third})
+	assert.Equal(t, []uuid.UUID{first, third}, mb.GetCouncil())
+	assert.Equal(t, 2, mb.GetCouncilTerms(first), "filling a vacancy should not start a new term")
+	assert.Equal(t, 0, mb.GetCouncilTerms(second))
+	assert.Equal(t, 1, mb.GetCouncilTerms(third))
+	assert.Equal(t, 1, mb.GetRulerTenure(), "filling a vacancy should not reset the tenure of the council")
+}
@@ -0,0 +1,58 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultEnergyModelMatchesRules(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	em := objects.GetEnergyModel(config)
+	mb := objects.GetMegaBike(config, utils.NewRand(1))
+
+	assert.Equal(t, 0.5*config.MovingDepletion, em.RidingCost(utils.Forces{Pedal: 0.5, Brake: 0.5}, mb))
+	assert.Equal(t, config.DeliberativeDemocracyPenalty, em.GovernanceCost(utils.Democracy))
+	assert.Equal(t, config.LeadershipDemocracyPenalty, em.GovernanceCost(utils.Leadership))
+	assert.Equal(t, 0.0, em.GovernanceCost(utils.Dictatorship))
+	assert.Equal(t, config.CouncilDemocracyPenalty, em.GovernanceCost(utils.Council))
+	assert.Equal(t, -config.LimboEnergyPenalty, em.LimboCost())
+	assert.Equal(t, 0.0, em.BasalCost())
+}
+
+func TestEnergyModelCosts(t *testing.T) {
+	em := objects.GetEnergyModel(utils.DefaultSimConfig())
+	em.MovingDepletion = 1.0
+	em.PedalExponent = 2.0
+	em.BrakingCost = 0.5
+	em.SteeringCost = 0.25
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+
+	assert.Equal(t, 0.25, em.RidingCost(utils.Forces{Pedal: 0.5}, mb), "pedalling costs should follow the curve")
+	assert.Equal(t, 0.5, em.RidingCost(utils.Forces{Brake: 1.0}, mb))
+	steering := utils.Forces{Turning: utils.TurningDecision{SteerBike: true, SteeringForce: -1.0}}
+	assert.Equal(t, 0.25, em.RidingCost(steering, mb))
+	steering.Turning.SteerBike = false
+	assert.Equal(t, 0.0, em.RidingCost(steering, mb), "agents that do not steer should not pay for it")
+}
+
+func TestLoadCostFallsOnPedallingRiders(t *testing.T) {
+	em := objects.GetEnergyModel(utils.DefaultSimConfig())
+	em.MovingDepletion = 0.0
+	em.LoadCost = 0.1
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	pedalling, freeRiding := NewMockBiker(), NewMockBiker()
+	pedalling.SetForces(utils.Forces{Pedal: 1.0})
+	mb.AddAgent(pedalling)
+	mb.AddAgent(freeRiding)
+	mb.UpdateMass()
+
+	alone := em.RidingCost(pedalling.GetForces(), mb)
+	assert.InDelta(t, 0.1*mb.GetPhysicalState().Mass, alone, 1e-9)
+	assert.Equal(t, 0.0, em.RidingCost(freeRiding.GetForces(), mb))
+
+	freeRiding.SetForces(utils.Forces{Pedal: 1.0})
+	assert.InDelta(t, alone/2, em.RidingCost(pedalling.GetForces(), mb), 1e-9, "the load should be shared between pedalling riders")
+}
@@ -0,0 +1,96 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// claimingBiker claims a fixed fraction of contested lootboxes
+type claimingBiker struct {
+	*MockBiker
+	claim float64
+}
+
+func (cb *claimingBiker) DecideLootClaim(uuid.UUID, []uuid.UUID) float64 {
+	return cb.claim
+}
+
+// contestant returns a bike reaching a lootbox at the given time, with a rider for each claim pedalling at the given force
+func contestant(rng *rand.Rand, time float64, pedal float64, claims ...float64) objects.LootContestant {
+	bike := objects.GetMegaBike(utils.DefaultSimConfig(), rng)
+	for _, claim := range claims {
+		rider := &claimingBiker{MockBiker: NewMockBiker(), claim: claim}
+		rider.SetForces(utils.Forces{Pedal: pedal})
+		bike.AddAgent(rider)
+	}
+	return objects.LootContestant{Bike: bike, TimeOfImpact: time}
+}
+
+func sharePolicy(rule utils.LootSharingRule) objects.ILootSharingPolicy {
+	config := utils.DefaultSimConfig()
+	config.LootSharing = rule
+	return objects.GetLootSharingPolicy(config)
+}
+
+func TestLootSharingPolicies(t *testing.T) {
+	rng := utils.NewRand(1)
+	lootBox := objects.GetLootBox(utils.DefaultSimConfig(), rng)
+	first := contestant(rng, 0.2, 1.0, 0.5, 0.5)
+	tied := contestant(rng, 0.2, 0.5, 0.5)
+	late := contestant(rng, 0.6, 0.5, 0.5)
+	contestants := []objects.LootContestant{first, tied, late}
+	firstID, tiedID, lateID := first.Bike.GetID(), tied.Bike.GetID(), late.Bike.GetID()
+
+	equal := sharePolicy(utils.EqualSplit).Share(lootBox, contestants)
+	assert.Len(t, equal, 3, "every bike reaching the lootbox should get a share")
+	for _, share := range equal {
+		assert.InDelta(t, 1.0/3.0, share, 1e-9)
+	}
+	assert.Equal(t, map[uuid.UUID]float64{firstID: 1.0}, sharePolicy(utils.FirstArrival).Share(lootBox, contestants))
+	assert.Equal(t, map[uuid.UUID]float64{firstID: 0.5, tiedID: 0.25, lateID: 0.25}, sharePolicy(utils.RiderShare).Share(lootBox, contestants))
+
+	effort := sharePolicy(utils.EffortShare).Share(lootBox, contestants)
+	assert.InDelta(t, 2.0/3.0, effort[firstID], 1e-9)
+	assert.InDelta(t, 1.0/6.0, effort[tiedID], 1e-9)
+	assert.InDelta(t, 1.0/6.0, effort[lateID], 1e-9)
+}
+
+func TestStaggeredArrivals(t *testing.T) {
+	rng := utils.NewRand(1)
+	lootBox := objects.GetLootBox(utils.DefaultSimConfig(), rng)
+	early := contestant(rng, 0.1, 1.0, 0.5)
+	late := contestant(rng, 0.9, 1.0, 0.5)
+	contestants := []objects.LootContestant{early, late}
+
+	assert.Equal(t, map[uuid.UUID]float64{early.Bike.GetID(): 0.5, late.Bike.GetID(): 0.5}, sharePolicy(utils.EqualSplit).Share(lootBox, contestants),
+		"an equal split should not depend on the time of arrival")
+	assert.Equal(t, map[uuid.UUID]float64{early.Bike.GetID(): 1.0}, sharePolicy(utils.FirstArrival).Share(lootBox, contestants),
+		"the bike arriving first should take everything")
+}
+
+func TestBargainingPolicy(t *testing.T) {
+	rng := utils.NewRand(1)
+	lootBox := objects.GetLootBox(utils.DefaultSimConfig(), rng)
+	modest := contestant(rng, 0.1, 1.0, 0.2, 0.4)
+	greedy := contestant(rng, 0.5, 1.0, 0.6)
+	policy := sharePolicy(utils.Bargaining)
+
+	shares := policy.Share(lootBox, []objects.LootContestant{modest, greedy})
+	assert.InDelta(t, 0.3, shares[modest.Bike.GetID()], 1e-9, "a democracy should claim the mean of its riders' claims")
+	assert.InDelta(t, 0.6, shares[greedy.Bike.GetID()], 1e-9)
+
+	greedier := contestant(rng, 0.5, 1.0, 0.8)
+	assert.Empty(t, policy.Share(lootBox, []objects.LootContestant{modest, greedier}), "incompatible claims should leave everyone empty handed")
+
+	// the ruler makes the claim of a bike it leads
+	modest.Bike.SetGovernance(utils.Dictatorship)
+	modest.Bike.SetRuler(modest.Bike.GetAgents()[0].GetID())
+	shares = policy.Share(lootBox, []objects.LootContestant{modest, greedier})
+	assert.InDelta(t, 0.2, shares[modest.Bike.GetID()], 1e-9)
+	assert.InDelta(t, 0.8, shares[greedier.Bike.GetID()], 1e-9)
+}
@@ -0,0 +1,111 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// spawnLootBoxes spawns n lootboxes from a single policy built from the config
+func spawnLootBoxes(config utils.SimConfig, n int) []*objects.LootBox {
+	rng := utils.NewRand(1)
+	policy := objects.GetLootBoxSpawnPolicy(config, rng)
+	lootBoxes := make([]*objects.LootBox, n)
+	for i := range lootBoxes {
+		lootBoxes[i] = objects.SpawnLootBox(config, rng, policy)
+	}
+	return lootBoxes
+}
+
+func TestDefaultLootBoxSpawnMatchesRules(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	for _, lootBox := range spawnLootBoxes(config, 100) {
+		assert.GreaterOrEqual(t, lootBox.GetTotalResources(), 5.0)
+		assert.LessOrEqual(t, lootBox.GetTotalResources(), 8.0)
+		position := lootBox.GetPosition()
+		assert.True(t, position.X >= 0 && position.X <= config.GridWidth && position.Y >= 0 && position.Y <= config.GridHeight)
+	}
+
+	// a policy built from the default config spawns the same lootboxes as GetLootBox
+	rng := utils.NewRand(2)
+	policy := objects.GetLootBoxSpawnPolicy(config, rng)
+	assert.Equal(t, objects.GetLootBox(config, utils.NewRand(2)), objects.SpawnLootBox(config, rng, policy))
+}
+
+func TestHotspotPlacement(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootBoxPlacement = utils.HotspotPlacement
+	config.LootBoxHotspots = 1
+	config.LootBoxHotspotSpread = 1.0
+	lootBoxes := spawnLootBoxes(config, 200)
+
+	var centre utils.Coordinates
+	for _, lootBox := range lootBoxes {
+		centre.X += lootBox.GetPosition().X / float64(len(lootBoxes))
+		centre.Y += lootBox.GetPosition().Y / float64(len(lootBoxes))
+	}
+	for _, lootBox := range lootBoxes {
+		distance := math.Hypot(lootBox.GetPosition().X-centre.X, lootBox.GetPosition().Y-centre.Y)
+		assert.Less(t, distance, 6.0, "lootboxes should cluster around the hotspot")
+	}
+}
+
+func TestZonedColours(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootBoxColouring = utils.ZonedColours
+	zoneWidth := config.GridWidth / float64(utils.NumOfColours)
+	for _, lootBox := range spawnLootBoxes(config, 100) {
+		zone := utils.Colour(min(int(lootBox.GetPosition().X/zoneWidth), int(utils.NumOfColours)-1))
+		assert.Equal(t, zone, lootBox.GetColour())
+	}
+}
+
+func TestResourceDistributions(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootBoxMinResources = 2.0
+	config.LootBoxMaxResources = 6.0
+
+	config.LootBoxResources = utils.NormalResources
+	for _, lootBox := range spawnLootBoxes(config, 500) {
+		assert.GreaterOrEqual(t, lootBox.GetTotalResources(), 2.0)
+		assert.LessOrEqual(t, lootBox.GetTotalResources(), 6.0)
+	}
+
+	config.LootBoxResources = utils.ExponentialResources
+	total := 0.0
+	lootBoxes := spawnLootBoxes(config, 5000)
+	for _, lootBox := range lootBoxes {
+		assert.GreaterOrEqual(t, lootBox.GetTotalResources(), 2.0)
+		total += lootBox.GetTotalResources()
+	}
+	assert.InDelta(t, 4.0, total/float64(len(lootBoxes)), 0.1, "exponential resources should average the middle of the range")
+
+	// a rich region covering the whole grid doubles every lootbox
+	config.LootBoxResources = utils.UniformResources
+	config.LootBoxRichRegions = 1
+	config.LootBoxRichRadius = 2 * math.Hypot(config.GridWidth, config.GridHeight)
+	for _, lootBox := range spawnLootBoxes(config, 100) {
+		assert.GreaterOrEqual(t, lootBox.GetTotalResources(), 4.0)
+		assert.LessOrEqual(t, lootBox.GetTotalResources(), 12.0)
+	}
+}
+
+func TestScarcityPhases(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	policy := objects.GetLootBoxSpawnPolicy(config, utils.NewRand(1))
+	for round := 0; round < 10; round++ {
+		assert.True(t, policy.Replenishes(round), "lootboxes are always replenished by default")
+	}
+
+	config.LootBoxScarcityPeriod = 4
+	config.LootBoxScarcityRounds = 1
+	policy = objects.GetLootBoxSpawnPolicy(config, utils.NewRand(1))
+	replenishes := make([]bool, 8)
+	for round := range replenishes {
+		replenishes[round] = policy.Replenishes(round)
+	}
+	assert.Equal(t, []bool{true, true, true, false, true, true, true, false}, replenishes)
+}
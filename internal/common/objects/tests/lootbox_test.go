@@ -0,0 +1,42 @@
+package objects
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLootBoxDecayAndExpiry(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	lootBox := objects.GetLootBox(config, utils.NewRand(1))
+	resources := lootBox.GetTotalResources()
+	lootBox.Age()
+	assert.Equal(t, resources, lootBox.GetTotalResources(), "lootboxes do not decay by default")
+	assert.Equal(t, -1, lootBox.GetTimeToLive(), "lootboxes never expire by default")
+
+	config.LootBoxDecay = 0.25
+	config.LootBoxLifetime = 2
+	lootBox = objects.GetLootBox(config, utils.NewRand(1))
+	assert.Equal(t, 2, lootBox.GetTimeToLive())
+	lootBox.Age()
+	assert.InDelta(t, 0.75*resources, lootBox.GetTotalResources(), 1e-9)
+	assert.Equal(t, 1, lootBox.GetTimeToLive())
+	lootBox.Age()
+	assert.InDelta(t, 0.75*0.75*resources, lootBox.GetTotalResources(), 1e-9)
+	assert.Equal(t, 0, lootBox.GetTimeToLive(), "the lootbox should have expired")
+}
+
+func TestDriftingLootBox(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	static := objects.GetLootBox(config, utils.NewRand(1))
+	static.UpdateForce()
+	assert.Equal(t, 0.0, static.GetForce())
+
+	config.LootBoxDriftForce = 0.5
+	drifting := objects.GetLootBox(config, utils.NewRand(1))
+	drifting.UpdateForce()
+	assert.Equal(t, 0.5, drifting.GetForce())
+	assert.Equal(t, config.MassLootBox, drifting.GetPhysicalState().Mass)
+}
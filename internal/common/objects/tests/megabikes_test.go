@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 )
 
 type MockBiker struct {
@@ -23,8 +24,11 @@ type MegaBike struct {
 	kickedOutCount int
 }
 
+// mock bikers draw from a seeded generator, so that they are different but the same in every run
+var mockRand = utils.NewRand(1)
+
 func NewMockBiker() *MockBiker {
-	baseBiker := objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New())
+	baseBiker := objects.GetBaseBiker(utils.NumOfColours, uuid.New(), mockRand)
 
 	return &MockBiker{
 		BaseBiker: baseBiker,
@@ -49,7 +53,7 @@ func (mb *MockBiker) GetID() uuid.UUID {
 }
 
 func TestGetMegaBike(t *testing.T) {
-	mb := objects.GetMegaBike()
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
 
 	if mb == nil {
 		t.Errorf("GetMegaBike returned nil")
@@ -65,7 +69,7 @@ func TestGetMegaBike(t *testing.T) {
 }
 
 func TestAddAgent(t *testing.T) {
-	mb := objects.GetMegaBike()
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
 	biker := NewMockBiker()
 
 	mb.AddAgent(biker)
@@ -80,7 +84,7 @@ func TestAddAgent(t *testing.T) {
 }
 
 func TestRemoveAgent(t *testing.T) {
-	mb := objects.GetMegaBike()
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
 	biker1 := NewMockBiker()
 	biker2 := NewMockBiker()
 
@@ -105,7 +109,7 @@ func TestRemoveAgent(t *testing.T) {
 }
 
 func TestUpdateMass(t *testing.T) {
-	mb := objects.GetMegaBike()
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
 	initialMass := mb.GetPhysicalState().Mass
 
 	mb.AddAgent(NewMockBiker())
@@ -122,7 +126,7 @@ func TestUpdateMass(t *testing.T) {
 }
 
 func TestGetSetGovernanceAndRuler(t *testing.T) {
-	mb := objects.GetMegaBike()
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
 	originalGovernance := mb.GetGovernance()
 	originalRuler := mb.GetRuler()
 
@@ -153,7 +157,7 @@ func TestKickOutAgent(t *testing.T) {
 	}
 	s.FoundingInstitutions()
 
-	mb := objects.GetMegaBike()
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
 
 	//biker1 := NewMockBiker(uuid.New(), map[uuid.UUID]int{ /* votes */ })
 	biker1 := NewMockBiker()
@@ -203,3 +207,113 @@ func TestKickOutAgent(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateForceSubtractsBrakes(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	pedalling, braking := NewMockBiker(), NewMockBiker()
+	pedalling.SetForces(utils.Forces{Pedal: 1.0, Brake: 0.25})
+	braking.SetForces(utils.Forces{Pedal: 0.0, Brake: 0.5})
+	mb.AddAgent(pedalling)
+	mb.AddAgent(braking)
+
+	mb.UpdateForce()
+	if mb.GetForce() != 0.25 {
+		t.Errorf("UpdateForce should sum pedal minus brake of every biker: got %v, want 0.25", mb.GetForce())
+	}
+}
+
+func TestTurningIsLimitedByMomentum(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	steering := utils.Forces{Turning: utils.TurningDecision{SteerBike: true, SteeringForce: 0.75}}
+
+	newBike := func(velocity float64) *objects.MegaBike {
+		mb := objects.GetMegaBike(config, utils.NewRand(1))
+		biker := NewMockBiker()
+		biker.SetForces(steering)
+		mb.AddAgent(biker)
+		mb.UpdateMass()
+		mb.SetPhysicalState(utils.PhysicalState{Velocity: velocity, Mass: mb.GetPhysicalState().Mass})
+		mb.SetOrientation(0)
+		return mb
+	}
+
+	stationary := newBike(0)
+	stationary.UpdateOrientation()
+	if stationary.GetOrientation() != 0.75 {
+		t.Errorf("a stationary bike should turn freely: got %v, want 0.75", stationary.GetOrientation())
+	}
+
+	moving := newBike(4)
+	maxTurn := moving.GetMaxTurn()
+	if maxTurn >= 0.75 {
+		t.Fatalf("a moving bike should not be able to turn as sharply: max turn %v", maxTurn)
+	}
+	moving.UpdateOrientation()
+	if moving.GetOrientation() != maxTurn {
+		t.Errorf("the turn should be limited to %v, got %v", maxTurn, moving.GetOrientation())
+	}
+	expectedVelocity := 4 * (1 - config.TurnMomentumLoss*maxTurn)
+	if moving.GetVelocity() != expectedVelocity {
+		t.Errorf("turning should cost momentum: got velocity %v, want %v", moving.GetVelocity(), expectedVelocity)
+	}
+}
+
+func TestShield(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	if mb.GetShield() != 0 {
+		t.Errorf("a new bike should have no shield: got %v", mb.GetShield())
+	}
+	mb.AddShield(2.0)
+	mb.AddShield(-0.5)
+	if mb.GetShield() != 1.5 {
+		t.Errorf("the shield should pool contributions: got %v, want 1.5", mb.GetShield())
+	}
+	mb.AddShield(-3.0)
+	if mb.GetShield() != 0 {
+		t.Errorf("the shield cannot go below 0: got %v", mb.GetShield())
+	}
+}
+
+func TestFinePool(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	assert.Zero(t, mb.GetFinePool(), "a new bike should have no fines")
+	mb.AddFinePool(0.1)
+	mb.AddFinePool(0.2)
+	assert.InDelta(t, 0.3, mb.GetFinePool(), 1e-9, "the fines should be pooled")
+	mb.AddFinePool(-1.0)
+	assert.Zero(t, mb.GetFinePool(), "the fine pool cannot go below 0")
+}
+
+func TestCouncilKickOut(t *testing.T) {
+	mb := objects.GetMegaBike(utils.DefaultSimConfig(), utils.NewRand(1))
+	bikers := []*MockBiker{NewMockBiker(), NewMockBiker(), NewMockBiker(), NewMockBiker()}
+	for _, biker := range bikers {
+		mb.AddAgent(biker)
+	}
+	mb.SetGovernance(utils.Council)
+	mb.SetCouncil([]uuid.UUID{bikers[0].GetID(), bikers[1].GetID()})
+	weights := map[uuid.UUID]float64{bikers[0].GetID(): 1.0, bikers[1].GetID(): 1.0}
+
+	// the riders who aren't on the council have no say
+	bikers[2].VoteMap[bikers[1].GetID()] = 1
+	bikers[3].VoteMap[bikers[1].GetID()] = 1
+	bikers[0].VoteMap[bikers[3].GetID()] = 1
+	assert.Empty(t, mb.CouncilKickOut(weights), "half the council should not be enough by default")
+
+	bikers[1].VoteMap[bikers[3].GetID()] = 1
+	assert.Equal(t, []uuid.UUID{bikers[3].GetID()}, mb.CouncilKickOut(weights))
+
+	// the threshold is a fraction of the total weight of the council
+	delete(bikers[1].VoteMap, bikers[3].GetID())
+	weights[bikers[0].GetID()], weights[bikers[1].GetID()] = 0.6, 0.2
+	assert.Equal(t, []uuid.UUID{bikers[3].GetID()}, mb.CouncilKickOut(weights))
+	weights[bikers[0].GetID()], weights[bikers[1].GetID()] = 1.5, 2.0
+	assert.Empty(t, mb.CouncilKickOut(weights))
+}
+
+func TestSeededBaseBiker(t *testing.T) {
+	first := objects.GetBaseBiker(utils.NumOfColours, uuid.Nil, utils.NewRand(7))
+	second := objects.GetBaseBiker(utils.NumOfColours, uuid.Nil, utils.NewRand(7))
+	assert.Equal(t, first.GetID(), second.GetID(), "bikers drawn from the same seed should be the same")
+	assert.Equal(t, first.GetColour(), second.GetColour())
+}
@@ -20,7 +20,7 @@ type ExtendedBaseBiker struct {
 // Produce new IExtendedBaseBiker
 func NewExtendedBaseBiker(agentId uuid.UUID) *ExtendedBaseBiker {
 	return &ExtendedBaseBiker{
-		BaseBiker: obj.GetBaseBiker(utils.GenerateRandomColour(), uuid.New()),
+		BaseBiker: obj.GetBaseBiker(utils.NumOfColours, uuid.New(), mockRand),
 	}
 }
 
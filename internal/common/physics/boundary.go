@@ -0,0 +1,113 @@
+package physics
+
+import (
+	utils "SOMAS2023/internal/common/utils"
+	"math"
+)
+
+/*
+The Boundary keeps the physics objects on the grid according to the boundary mode of the simulation,
+and measures distances the way objects travel (i.e. across the edges of a torus)
+*/
+
+type Boundary struct {
+	Mode   utils.BoundaryMode `json:"mode"`
+	Width  float64            `json:"width"`
+	Height float64            `json:"height"`
+}
+
+// NewBoundary creates the boundary of the grid described by the simulation config
+func NewBoundary(config utils.SimConfig) Boundary {
+	return Boundary{
+		Mode:   config.Boundary,
+		Width:  config.GridWidth,
+		Height: config.GridHeight,
+	}
+}
+
+// Apply brings an object that has left the grid back onto it and returns its new state and orientation.
+// Clamped objects are stopped at the edge, reflected ones have their orientation mirrored by the edge
+// they bounced off and wrapped ones re-enter on the opposite side.
+func (b Boundary) Apply(state utils.PhysicalState, orientation float64) (utils.PhysicalState, float64) {
+	position := state.Position
+	switch b.Mode {
+	case utils.Clamp:
+		state.Position.X = math.Max(0, math.Min(position.X, b.Width))
+		state.Position.Y = math.Max(0, math.Min(position.Y, b.Height))
+		if state.Position != position {
+			state.Velocity = 0
+			state.Acceleration = 0
+		}
+	case utils.Reflect:
+		var bouncedX, bouncedY bool
+		state.Position.X, bouncedX = reflect(position.X, b.Width)
+		state.Position.Y, bouncedY = reflect(position.Y, b.Height)
+		// orientations are in units of pi, a vertical edge mirrors the angle around pi/2 and
+		// a horizontal one around 0
+		if bouncedX {
+			orientation = 1 - orientation
+		}
+		if bouncedY {
+			orientation = -orientation
+		}
+		orientation = normaliseOrientation(orientation)
+	case utils.Torus:
+		state.Position.X = wrap(position.X, b.Width)
+		state.Position.Y = wrap(position.Y, b.Height)
+	}
+	return state, orientation
+}
+
+// Displacement returns the shortest vector from source to target. On a torus it may cross the edges of the grid.
+func (b Boundary) Displacement(src utils.Coordinates, target utils.Coordinates) (float64, float64) {
+	xDiff := target.X - src.X
+	yDiff := target.Y - src.Y
+	if b.Mode == utils.Torus {
+		xDiff -= b.Width * math.Round(xDiff/b.Width)
+		yDiff -= b.Height * math.Round(yDiff/b.Height)
+	}
+	return xDiff, yDiff
+}
+
+// ComputeDistance is the boundary aware version of ComputeDistance, it returns the squared distance
+func (b Boundary) ComputeDistance(src utils.Coordinates, target utils.Coordinates) float64 {
+	xDiff, yDiff := b.Displacement(src, target)
+	return math.Pow(xDiff, 2) + math.Pow(yDiff, 2)
+}
+
+// ComputeOrientation is the boundary aware version of ComputeOrientation
+func (b Boundary) ComputeOrientation(src utils.Coordinates, target utils.Coordinates) float64 {
+	xDiff, yDiff := b.Displacement(src, target)
+	return math.Atan2(yDiff, xDiff) / math.Pi
+}
+
+// reflect folds a coordinate back into [0, size] and reports whether it bounced an odd number of times
+func reflect(coordinate float64, size float64) (float64, bool) {
+	if coordinate >= 0 && coordinate <= size {
+		return coordinate, false
+	}
+	folded := wrap(coordinate, 2*size)
+	bounced := int(math.Floor(coordinate/size))%2 != 0
+	if folded > size {
+		folded = 2*size - folded
+	}
+	return folded, bounced
+}
+
+func wrap(coordinate float64, size float64) float64 {
+	coordinate = math.Mod(coordinate, size)
+	if coordinate < 0 {
+		coordinate += size
+	}
+	return coordinate
+}
+
+// normaliseOrientation brings an orientation back to the range [-1, 1]
+func normaliseOrientation(orientation float64) float64 {
+	if orientation > 1.0 {
+		orientation -= 2.0
+	} else if orientation < -1.0 {
+		orientation += 2.0
+	}
+	return orientation
+}
@@ -0,0 +1,44 @@
+package physics
+
+import (
+	utils "SOMAS2023/internal/common/utils"
+	"math"
+)
+
+// TimeOfImpact sweeps two objects along the straight lines from their start to their end positions
+// over a step, and returns the fraction of the step (between 0 and 1) at which they first come closer
+// than threshold. The second value is false if they never do. Objects that are already closer than
+// threshold at the start of the step collide at time 0.
+func (b Boundary) TimeOfImpact(startA, endA, startB, endB utils.Coordinates, threshold float64) (float64, bool) {
+	// work in the frame of B: A starts at separation and moves by relative over the step
+	separationX, separationY := b.Displacement(startB, startA)
+	movedAX, movedAY := b.Displacement(startA, endA)
+	movedBX, movedBY := b.Displacement(startB, endB)
+	relativeX, relativeY := movedAX-movedBX, movedAY-movedBY
+
+	// solve |separation + t * relative|^2 = threshold^2 for the smallest t in [0, 1]
+	a := relativeX*relativeX + relativeY*relativeY
+	halfB := separationX*relativeX + separationY*relativeY
+	c := separationX*separationX + separationY*separationY - threshold*threshold
+	if c < 0 {
+		return 0, true
+	}
+	if a == 0 {
+		return 0, false
+	}
+	discriminant := halfB*halfB - a*c
+	if discriminant < 0 {
+		return 0, false
+	}
+	impact := (-halfB - math.Sqrt(discriminant)) / a
+	if impact < 0 || impact > 1 {
+		return 0, false
+	}
+	// the closest approach must be strictly within threshold, like the end of step check
+	closest := math.Max(0, math.Min(1, -halfB/a))
+	closestX, closestY := separationX+closest*relativeX, separationY+closest*relativeY
+	if closestX*closestX+closestY*closestY >= threshold*threshold {
+		return 0, false
+	}
+	return impact, true
+}
@@ -9,26 +9,33 @@ import (
 The Engine is responsible for calculating physics for the environment
 */
 
-func CalcAcceleration(f float64, m float64, v float64) float64 {
-	if m == 0 {
-		panic("zero mass")
+type Engine struct {
+	Model    IPhysicsModel
+	Boundary Boundary
+}
+
+// NewEngine creates a physics engine using the parameters of the simulation config
+func NewEngine(config utils.SimConfig) Engine {
+	return Engine{
+		Model:    NewModel(config),
+		Boundary: NewBoundary(config),
 	}
-	return (f - CalcDrag(v)) / m
+}
+
+// defaultModel is the model used by the package level functions, which agents can use to
+// make predictions about the environment
+var defaultModel = NewModel(utils.DefaultSimConfig())
+
+func CalcAcceleration(f float64, m float64, v float64) float64 {
+	return defaultModel.CalcAcceleration(f, m, v)
 }
 
 func CalcDrag(velocity float64) float64 {
-	return utils.DragCoefficient * math.Pow(velocity, 2)
+	return defaultModel.CalcDrag(velocity)
 }
 
 func CalcVelocity(acc float64, currVelocity float64) float64 {
-	var newVelocity float64
-	// dt is equal to one
-	if (currVelocity + (acc * 1)) < 0 {
-		newVelocity = 0.0
-	} else {
-		newVelocity = (acc * 1) + currVelocity
-	}
-	return newVelocity
+	return defaultModel.CalcVelocity(acc, currVelocity, defaultModel.TimeStep)
 }
 
 func GetNewPosition(coordinates utils.Coordinates, velocity float64, orientation float64) utils.Coordinates {
@@ -44,23 +51,17 @@ func ComputeOrientation(src utils.Coordinates, target utils.Coordinates) float64
 	return math.Atan2(yDiff, xDiff) / math.Pi
 }
 
-// ComputeDistance is to compute the L2 distance from source to target
+// ComputeDistance is to compute the squared L2 distance from source to target, ignoring the boundary of the grid
 func ComputeDistance(src utils.Coordinates, target utils.Coordinates) float64 {
 	return math.Pow(src.X-target.X, 2) + math.Pow(src.Y-target.Y, 2)
 }
 
 // This function is to be called from the server only
-func GenerateNewState(initialState utils.PhysicalState, force float64, orientation float64) utils.PhysicalState {
-	acceleration := CalcAcceleration(force, initialState.Mass, initialState.Velocity)
-	velocity := CalcVelocity(acceleration, initialState.Velocity)
-	coordinates := GetNewPosition(initialState.Position, velocity, orientation)
-
-	finalState := utils.PhysicalState{
-		Position:     coordinates,
-		Acceleration: acceleration,
-		Velocity:     velocity,
-		Mass:         initialState.Mass,
-	}
+func (e Engine) GenerateNewState(initialState utils.PhysicalState, force float64, orientation float64) utils.PhysicalState {
+	return e.Model.GenerateNewState(initialState, force, orientation)
+}
 
-	return finalState
+// GenerateNewState predicts the next physical state using the default physics parameters
+func GenerateNewState(initialState utils.PhysicalState, force float64, orientation float64) utils.PhysicalState {
+	return defaultModel.GenerateNewState(initialState, force, orientation)
 }
@@ -0,0 +1,204 @@
+package physics
+
+import (
+	utils "SOMAS2023/internal/common/utils"
+	"bytes"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+/*
+The SpatialIndex is a uniform grid of cells over the positions of objects. Proximity queries only look at
+the cells around the queried position, so they stay fast in large worlds with many objects.
+*/
+
+type SpatialIndex struct {
+	cellWidth  float64
+	cellHeight float64
+	boundary   Boundary
+	cells      map[gridCell][]uuid.UUID
+	positions  map[uuid.UUID]utils.Coordinates
+	// extent of the occupied cells, queries never need to look beyond it
+	minCell gridCell
+	maxCell gridCell
+}
+
+type gridCell struct {
+	X int
+	Y int
+}
+
+// NewSpatialIndex creates an empty index with square cells of the given size. Distances are measured
+// according to the boundary, so on a torus queries wrap around the edges of the grid.
+func NewSpatialIndex(cellSize float64, boundary Boundary) *SpatialIndex {
+	cellSize = math.Max(cellSize, utils.Epsilon)
+	si := &SpatialIndex{
+		cellWidth:  cellSize,
+		cellHeight: cellSize,
+		boundary:   boundary,
+		cells:      make(map[gridCell][]uuid.UUID),
+		positions:  make(map[uuid.UUID]utils.Coordinates),
+	}
+	if boundary.Mode == utils.Torus {
+		// the cells must tile the torus exactly so that they line up when wrapping around
+		si.cellWidth = boundary.Width / math.Ceil(boundary.Width/cellSize)
+		si.cellHeight = boundary.Height / math.Ceil(boundary.Height/cellSize)
+	}
+	return si
+}
+
+// Insert adds an object to the index, objects are expected to be inserted once
+func (si *SpatialIndex) Insert(id uuid.UUID, position utils.Coordinates) {
+	cell := si.wrapCell(si.cellOf(position))
+	if len(si.positions) == 0 {
+		si.minCell, si.maxCell = cell, cell
+	} else {
+		si.minCell = gridCell{X: min(si.minCell.X, cell.X), Y: min(si.minCell.Y, cell.Y)}
+		si.maxCell = gridCell{X: max(si.maxCell.X, cell.X), Y: max(si.maxCell.Y, cell.Y)}
+	}
+	si.cells[cell] = append(si.cells[cell], id)
+	si.positions[id] = position
+}
+
+// Len returns the number of objects in the index
+func (si *SpatialIndex) Len() int {
+	return len(si.positions)
+}
+
+// Within returns the objects closer than radius to position, sorted from nearest to furthest (then by ID)
+func (si *SpatialIndex) Within(position utils.Coordinates, radius float64) []uuid.UUID {
+	return si.within(position, radius, nil)
+}
+
+// Nearest returns the k objects nearest to position that satisfy keep (every object if keep is nil),
+// sorted from nearest to furthest (then by ID). Fewer objects are returned if not enough satisfy keep.
+func (si *SpatialIndex) Nearest(position utils.Coordinates, k int, keep func(id uuid.UUID) bool) []uuid.UUID {
+	if k <= 0 || len(si.positions) == 0 {
+		return []uuid.UUID{}
+	}
+	// grow the search radius until it holds k objects or covers every occupied cell
+	coverAll := si.radiusCoveringAll(position)
+	for radius := math.Min(si.cellWidth, si.cellHeight); ; radius *= 2 {
+		found := si.within(position, radius, keep)
+		if len(found) >= k {
+			return found[:k]
+		}
+		if radius > coverAll {
+			return found
+		}
+	}
+}
+
+func (si *SpatialIndex) within(position utils.Coordinates, radius float64, keep func(id uuid.UUID) bool) []uuid.UUID {
+	found := make([]uuid.UUID, 0)
+	distances := make(map[uuid.UUID]float64)
+	for _, cell := range si.cellsAround(position, radius) {
+		for _, id := range si.cells[cell] {
+			if keep != nil && !keep(id) {
+				continue
+			}
+			distance := si.boundary.ComputeDistance(position, si.positions[id])
+			if distance < radius*radius {
+				found = append(found, id)
+				distances[id] = distance
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if distances[found[i]] != distances[found[j]] {
+			return distances[found[i]] < distances[found[j]]
+		}
+		return bytes.Compare(found[i][:], found[j][:]) < 0
+	})
+	return found
+}
+
+// cellsAround returns the occupied cells that may hold objects closer than radius to position
+func (si *SpatialIndex) cellsAround(position utils.Coordinates, radius float64) []gridCell {
+	from := si.cellOf(utils.Coordinates{X: position.X - radius, Y: position.Y - radius})
+	to := si.cellOf(utils.Coordinates{X: position.X + radius, Y: position.Y + radius})
+	if si.boundary.Mode == utils.Torus {
+		// no need to go round the torus more than once
+		columns, rows := si.cellCounts()
+		to = gridCell{X: min(to.X, from.X+columns-1), Y: min(to.Y, from.Y+rows-1)}
+	} else {
+		// no need to look beyond the occupied cells
+		from = gridCell{X: max(from.X, si.minCell.X), Y: max(from.Y, si.minCell.Y)}
+		to = gridCell{X: min(to.X, si.maxCell.X), Y: min(to.Y, si.maxCell.Y)}
+	}
+
+	cells := make([]gridCell, 0)
+	if (to.X-from.X+1)*(to.Y-from.Y+1) > len(si.cells) {
+		// the objects are sparse compared to the area searched, go through the occupied cells instead
+		for cell := range si.cells {
+			if si.cellInRange(cell, from, to) {
+				cells = append(cells, cell)
+			}
+		}
+		return cells
+	}
+	for x := from.X; x <= to.X; x++ {
+		for y := from.Y; y <= to.Y; y++ {
+			cell := si.wrapCell(gridCell{X: x, Y: y})
+			if _, occupied := si.cells[cell]; occupied {
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
+
+// cellInRange reports whether an occupied cell is one of the cells from the corner from to the corner to
+func (si *SpatialIndex) cellInRange(cell gridCell, from gridCell, to gridCell) bool {
+	if si.boundary.Mode == utils.Torus {
+		// shift the cell by whole turns of the torus to the right of from
+		columns, rows := si.cellCounts()
+		cell.X = from.X + ((cell.X-from.X)%columns+columns)%columns
+		cell.Y = from.Y + ((cell.Y-from.Y)%rows+rows)%rows
+	}
+	return cell.X >= from.X && cell.X <= to.X && cell.Y >= from.Y && cell.Y <= to.Y
+}
+
+// radiusCoveringAll returns a radius for which a query around position visits every occupied cell
+func (si *SpatialIndex) radiusCoveringAll(position utils.Coordinates) float64 {
+	if si.boundary.Mode == utils.Torus {
+		return math.Hypot(si.boundary.Width, si.boundary.Height)
+	}
+	corners := []utils.Coordinates{
+		{X: float64(si.minCell.X) * si.cellWidth, Y: float64(si.minCell.Y) * si.cellHeight},
+		{X: float64(si.maxCell.X+1) * si.cellWidth, Y: float64(si.maxCell.Y+1) * si.cellHeight},
+	}
+	radius := 0.0
+	for _, x := range []float64{corners[0].X, corners[1].X} {
+		for _, y := range []float64{corners[0].Y, corners[1].Y} {
+			radius = math.Max(radius, math.Hypot(x-position.X, y-position.Y))
+		}
+	}
+	return radius
+}
+
+// cellOf returns the cell holding position, on a torus it may lie outside of the grid until wrapped
+func (si *SpatialIndex) cellOf(position utils.Coordinates) gridCell {
+	return gridCell{
+		X: int(math.Floor(position.X / si.cellWidth)),
+		Y: int(math.Floor(position.Y / si.cellHeight)),
+	}
+}
+
+// wrapCell brings a cell outside of a torus back onto it
+func (si *SpatialIndex) wrapCell(cell gridCell) gridCell {
+	if si.boundary.Mode != utils.Torus {
+		return cell
+	}
+	columns, rows := si.cellCounts()
+	return gridCell{
+		X: ((cell.X % columns) + columns) % columns,
+		Y: ((cell.Y % rows) + rows) % rows,
+	}
+}
+
+func (si *SpatialIndex) cellCounts() (int, int) {
+	return int(math.Round(si.boundary.Width / si.cellWidth)), int(math.Round(si.boundary.Height / si.cellHeight))
+}
@@ -0,0 +1,72 @@
+package physics
+
+import (
+	utils "SOMAS2023/internal/common/utils"
+	"math"
+)
+
+// IPhysicsModel computes how the physical state of an object evolves over a round, given the force
+// applied to it and its orientation. The server can swap the model to study other dynamics.
+type IPhysicsModel interface {
+	GenerateNewState(initialState utils.PhysicalState, force float64, orientation float64) utils.PhysicalState
+}
+
+/*
+The NewtonianModel integrates the force applied to an object, minus the drag opposing its motion,
+over the duration of a round. The round can be split into sub-steps for a finer integration.
+*/
+
+type NewtonianModel struct {
+	Drag            utils.DragLaw
+	DragCoefficient float64
+	MaxVelocity     float64
+	TimeStep        float64 // duration of a round
+	SubSteps        int     // number of integration steps per round
+}
+
+// NewModel creates the physics model described by the simulation config
+func NewModel(config utils.SimConfig) NewtonianModel {
+	return NewtonianModel{
+		Drag:            config.Drag,
+		DragCoefficient: config.DragCoefficient,
+		MaxVelocity:     config.MaxVelocity,
+		TimeStep:        config.TimeStep,
+		SubSteps:        config.SubSteps,
+	}
+}
+
+func (m NewtonianModel) CalcAcceleration(f float64, mass float64, v float64) float64 {
+	if mass == 0 {
+		panic("zero mass")
+	}
+	return (f - m.CalcDrag(v)) / mass
+}
+
+func (m NewtonianModel) CalcDrag(velocity float64) float64 {
+	if m.Drag == utils.LinearDrag {
+		return m.DragCoefficient * velocity
+	}
+	return m.DragCoefficient * math.Pow(velocity, 2)
+}
+
+// CalcVelocity applies an acceleration over dt, the velocity stays between zero (bikes do not move
+// backwards) and the maximum velocity
+func (m NewtonianModel) CalcVelocity(acc float64, currVelocity float64, dt float64) float64 {
+	newVelocity := (acc * dt) + currVelocity
+	if newVelocity < 0 {
+		return 0.0
+	}
+	return math.Min(newVelocity, m.MaxVelocity)
+}
+
+func (m NewtonianModel) GenerateNewState(initialState utils.PhysicalState, force float64, orientation float64) utils.PhysicalState {
+	steps := max(1, m.SubSteps)
+	dt := m.TimeStep / float64(steps)
+	state := initialState
+	for step := 0; step < steps; step++ {
+		state.Acceleration = m.CalcAcceleration(force, state.Mass, state.Velocity)
+		state.Velocity = m.CalcVelocity(state.Acceleration, state.Velocity, dt)
+		state.Position = GetNewPosition(state.Position, state.Velocity*dt, orientation)
+	}
+	return state
+}
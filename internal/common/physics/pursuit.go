@@ -0,0 +1,47 @@
+package physics
+
+import (
+	utils "SOMAS2023/internal/common/utils"
+	"math"
+)
+
+// InterceptPoint predicts where a pursuer moving at speed can catch a target moving in a straight line at
+// targetVelocity with targetOrientation, and returns that point. The second value is false if the pursuer is
+// too slow to ever catch the target, in which case the target's current position is returned.
+func (b Boundary) InterceptPoint(pursuer utils.Coordinates, speed float64, target utils.Coordinates, targetVelocity float64, targetOrientation float64) (utils.Coordinates, bool) {
+	separationX, separationY := b.Displacement(pursuer, target)
+	velocityX := targetVelocity * math.Cos(math.Pi*targetOrientation)
+	velocityY := targetVelocity * math.Sin(math.Pi*targetOrientation)
+
+	// solve |separation + t * velocity| = speed * t for the smallest t > 0
+	a := velocityX*velocityX + velocityY*velocityY - speed*speed
+	halfB := separationX*velocityX + separationY*velocityY
+	c := separationX*separationX + separationY*separationY
+	interception := math.Inf(1)
+	if math.Abs(a) < utils.Epsilon*utils.Epsilon {
+		// as fast as the target, it can only be caught if it is coming closer
+		if halfB < 0 {
+			interception = -c / (2 * halfB)
+		}
+	} else if discriminant := halfB*halfB - a*c; discriminant >= 0 {
+		for _, t := range []float64{(-halfB - math.Sqrt(discriminant)) / a, (-halfB + math.Sqrt(discriminant)) / a} {
+			if t > 0 && t < interception {
+				interception = t
+			}
+		}
+	}
+	if math.IsInf(interception, 1) {
+		return target, false
+	}
+	return utils.Coordinates{X: target.X + interception*velocityX, Y: target.Y + interception*velocityY}, true
+}
+
+// Turn rotates orientation towards desired by at most maxTurn (in the units of the orientation, 1.0 is 180°),
+// going the shortest way round
+func Turn(orientation float64, desired float64, maxTurn float64) float64 {
+	difference := normaliseOrientation(desired - orientation)
+	if math.Abs(difference) <= maxTurn {
+		return desired
+	}
+	return normaliseOrientation(orientation + math.Copysign(maxTurn, difference))
+}
@@ -0,0 +1,69 @@
+package physics_test
+
+import (
+	"SOMAS2023/internal/common/physics"
+	"SOMAS2023/internal/common/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func boundary(mode utils.BoundaryMode) physics.Boundary {
+	return physics.Boundary{Mode: mode, Width: 10, Height: 20}
+}
+
+func outsideState() utils.PhysicalState {
+	return utils.PhysicalState{
+		Position: utils.Coordinates{X: 12, Y: -3},
+		Velocity: 4,
+		Mass:     1,
+	}
+}
+
+func TestUnboundedLeavesObjectsOffTheGrid(t *testing.T) {
+	state, orientation := boundary(utils.Unbounded).Apply(outsideState(), 0.25)
+	assert.Equal(t, outsideState(), state)
+	assert.Equal(t, 0.25, orientation)
+}
+
+func TestClampStopsObjectsAtTheEdge(t *testing.T) {
+	state, orientation := boundary(utils.Clamp).Apply(outsideState(), 0.25)
+	assert.Equal(t, utils.Coordinates{X: 10, Y: 0}, state.Position)
+	assert.Equal(t, 0.0, state.Velocity)
+	assert.Equal(t, 0.25, orientation)
+
+	inside := utils.PhysicalState{Position: utils.Coordinates{X: 5, Y: 5}, Velocity: 4, Mass: 1}
+	state, _ = boundary(utils.Clamp).Apply(inside, 0.25)
+	assert.Equal(t, inside, state, "objects on the grid should not be affected")
+}
+
+func TestReflectBouncesObjectsOffTheEdge(t *testing.T) {
+	state, orientation := boundary(utils.Reflect).Apply(outsideState(), -0.25)
+	assert.InDelta(t, 8, state.Position.X, utils.Epsilon)
+	assert.InDelta(t, 3, state.Position.Y, utils.Epsilon)
+	assert.Equal(t, 4.0, state.Velocity)
+	// heading down and right, it bounces off the right and bottom edges so heads up and left
+	assert.InDelta(t, 0.75, orientation, utils.Epsilon)
+
+	state, orientation = boundary(utils.Reflect).Apply(utils.PhysicalState{Position: utils.Coordinates{X: 5, Y: 21}}, 0.5)
+	assert.InDelta(t, 19, state.Position.Y, utils.Epsilon)
+	assert.InDelta(t, -0.5, orientation, utils.Epsilon)
+}
+
+func TestTorusWrapsObjectsAround(t *testing.T) {
+	state, orientation := boundary(utils.Torus).Apply(outsideState(), -0.25)
+	assert.InDelta(t, 2, state.Position.X, utils.Epsilon)
+	assert.InDelta(t, 17, state.Position.Y, utils.Epsilon)
+	assert.Equal(t, 4.0, state.Velocity)
+	assert.Equal(t, -0.25, orientation)
+}
+
+func TestTorusDistanceCrossesTheEdges(t *testing.T) {
+	left := utils.Coordinates{X: 1, Y: 1}
+	right := utils.Coordinates{X: 9, Y: 19}
+
+	assert.InDelta(t, physics.ComputeDistance(left, right), boundary(utils.Clamp).ComputeDistance(left, right), utils.Epsilon)
+	assert.InDelta(t, 8.0, boundary(utils.Torus).ComputeDistance(left, right), utils.Epsilon)
+	// the shortest way from left to right is down and to the left, across the corner
+	assert.InDelta(t, -0.75, boundary(utils.Torus).ComputeOrientation(left, right), utils.Epsilon)
+}
@@ -0,0 +1,42 @@
+package physics_test
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFastObjectsCannotTunnel(t *testing.T) {
+	lootbox := utils.Coordinates{X: 50, Y: 50}
+	// the bike starts and ends the step 20 units away from the lootbox, but goes through it
+	impact, collided := boundary(utils.Unbounded).TimeOfImpact(utils.Coordinates{X: 30, Y: 50}, utils.Coordinates{X: 70, Y: 50}, lootbox, lootbox, 7)
+	assert.True(t, collided)
+	assert.InDelta(t, 13.0/40.0, impact, utils.Epsilon)
+
+	_, collided = boundary(utils.Unbounded).TimeOfImpact(utils.Coordinates{X: 30, Y: 60}, utils.Coordinates{X: 70, Y: 60}, lootbox, lootbox, 7)
+	assert.False(t, collided, "a bike passing 10 units away should miss the lootbox")
+	_, collided = boundary(utils.Unbounded).TimeOfImpact(utils.Coordinates{X: 30, Y: 50}, utils.Coordinates{X: 40, Y: 50}, lootbox, lootbox, 7)
+	assert.False(t, collided, "a bike stopping short of the lootbox should miss it")
+}
+
+func TestTimeOfImpact(t *testing.T) {
+	start := utils.Coordinates{X: 5, Y: 5}
+	impact, collided := boundary(utils.Unbounded).TimeOfImpact(start, start, utils.Coordinates{X: 8, Y: 5}, utils.Coordinates{X: 8, Y: 5}, 7)
+	assert.True(t, collided, "stationary objects closer than the threshold collide")
+	assert.Equal(t, 0.0, impact)
+
+	// two objects heading towards each other meet earlier than if only one of them moved
+	impact, collided = boundary(utils.Unbounded).TimeOfImpact(utils.Coordinates{X: 0, Y: 0}, utils.Coordinates{X: 10, Y: 0}, utils.Coordinates{X: 17, Y: 0}, utils.Coordinates{X: 7, Y: 0}, 7)
+	assert.True(t, collided)
+	assert.InDelta(t, 0.5, impact, utils.Epsilon)
+}
+
+func TestTimeOfImpactAcrossTheTorus(t *testing.T) {
+	// on a 10 wide torus, moving from x = 8 to x = 2 crosses the edge rather than the middle of the grid
+	target := utils.Coordinates{X: 0, Y: 10}
+	_, collided := boundary(utils.Torus).TimeOfImpact(utils.Coordinates{X: 8, Y: 10}, utils.Coordinates{X: 2, Y: 10}, target, target, 1)
+	assert.True(t, collided)
+	_, collided = boundary(utils.Unbounded).TimeOfImpact(utils.Coordinates{X: 8, Y: 10}, utils.Coordinates{X: 2, Y: 10}, target, target, 1)
+	assert.False(t, collided)
+}
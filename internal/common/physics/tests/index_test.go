@@ -0,0 +1,92 @@
+package physics_test
+
+import (
+	"SOMAS2023/internal/common/physics"
+	"SOMAS2023/internal/common/utils"
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// bruteForceWithin is the reference the index is checked against: every object closer than radius
+func bruteForceWithin(b physics.Boundary, positions map[uuid.UUID]utils.Coordinates, position utils.Coordinates, radius float64) []uuid.UUID {
+	found := make([]uuid.UUID, 0)
+	for id, pos := range positions {
+		if b.ComputeDistance(position, pos) < radius*radius {
+			found = append(found, id)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		di, dj := b.ComputeDistance(position, positions[found[i]]), b.ComputeDistance(position, positions[found[j]])
+		if di != dj {
+			return di < dj
+		}
+		return bytes.Compare(found[i][:], found[j][:]) < 0
+	})
+	return found
+}
+
+func TestSpatialIndexWithin(t *testing.T) {
+	index := physics.NewSpatialIndex(2, boundary(utils.Unbounded))
+	near, far := uuid.New(), uuid.New()
+	index.Insert(near, utils.Coordinates{X: 1, Y: 1})
+	index.Insert(far, utils.Coordinates{X: 8, Y: 15})
+
+	assert.Equal(t, 2, index.Len())
+	assert.Equal(t, []uuid.UUID{near}, index.Within(utils.Coordinates{X: 0, Y: 0}, 2))
+	assert.Equal(t, []uuid.UUID{near, far}, index.Within(utils.Coordinates{X: 0, Y: 0}, 20))
+	assert.Empty(t, index.Within(utils.Coordinates{X: 5, Y: 5}, 1))
+	assert.Empty(t, index.Within(utils.Coordinates{X: 0, Y: 0}, 0), "distances must be strictly smaller than the radius")
+}
+
+func TestSpatialIndexNearest(t *testing.T) {
+	index := physics.NewSpatialIndex(1, boundary(utils.Unbounded))
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for i, id := range ids {
+		index.Insert(id, utils.Coordinates{X: float64(3 * i), Y: 0})
+	}
+
+	assert.Equal(t, ids[:2], index.Nearest(utils.Coordinates{X: -1, Y: 0}, 2, nil))
+	assert.Equal(t, ids, index.Nearest(utils.Coordinates{X: -1, Y: 0}, 5, nil), "fewer objects are returned when there are not enough")
+	skipFirst := func(id uuid.UUID) bool { return id != ids[0] }
+	assert.Equal(t, []uuid.UUID{ids[1]}, index.Nearest(utils.Coordinates{X: -1, Y: 0}, 1, skipFirst))
+	assert.Empty(t, physics.NewSpatialIndex(1, boundary(utils.Unbounded)).Nearest(utils.Coordinates{}, 1, nil))
+}
+
+func TestSpatialIndexWrapsAroundTorus(t *testing.T) {
+	index := physics.NewSpatialIndex(3, boundary(utils.Torus))
+	id := uuid.New()
+	index.Insert(id, utils.Coordinates{X: 9.5, Y: 19.5})
+
+	assert.Equal(t, []uuid.UUID{id}, index.Within(utils.Coordinates{X: 0.5, Y: 0.5}, 2))
+	assert.Equal(t, []uuid.UUID{id}, index.Nearest(utils.Coordinates{X: 0.5, Y: 0.5}, 1, nil))
+	unbounded := physics.NewSpatialIndex(3, boundary(utils.Unbounded))
+	unbounded.Insert(id, utils.Coordinates{X: 9.5, Y: 19.5})
+	assert.Empty(t, unbounded.Within(utils.Coordinates{X: 0.5, Y: 0.5}, 2))
+}
+
+func TestSpatialIndexMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, mode := range []utils.BoundaryMode{utils.Unbounded, utils.Torus} {
+		b := boundary(mode)
+		index := physics.NewSpatialIndex(1.5, b)
+		positions := make(map[uuid.UUID]utils.Coordinates)
+		for i := 0; i < 50; i++ {
+			id := utils.NewUUID(rng)
+			positions[id] = utils.Coordinates{X: rng.Float64() * b.Width, Y: rng.Float64() * b.Height}
+			index.Insert(id, positions[id])
+		}
+		for i := 0; i < 50; i++ {
+			position := utils.Coordinates{X: rng.Float64()*14 - 2, Y: rng.Float64()*24 - 2}
+			radius := rng.Float64() * 8
+			expected := bruteForceWithin(b, positions, position, radius)
+			assert.Equal(t, expected, index.Within(position, radius), mode.String())
+			all := bruteForceWithin(b, positions, position, 100)
+			assert.Equal(t, all[:3], index.Nearest(position, 3, nil), mode.String())
+		}
+	}
+}
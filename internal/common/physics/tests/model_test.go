@@ -0,0 +1,75 @@
+package physics_test
+
+import (
+	"SOMAS2023/internal/common/physics"
+	"SOMAS2023/internal/common/utils"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func model() physics.NewtonianModel {
+	return physics.NewModel(utils.DefaultSimConfig())
+}
+
+func stationary(mass float64) utils.PhysicalState {
+	return utils.PhysicalState{Mass: mass}
+}
+
+func TestVelocityCannotExceedMaximum(t *testing.T) {
+	m := model()
+	m.MaxVelocity = 2
+	state := m.GenerateNewState(stationary(1), 100, 0)
+	assert.Equal(t, 2.0, state.Velocity)
+	assert.InDelta(t, 2.0, state.Position.X, 1e-9)
+
+	assert.Equal(t, 0.0, physics.CalcVelocity(-10, 1), "bikes do not move backwards")
+	assert.Equal(t, utils.MaxVelocity, physics.CalcVelocity(100, 0))
+}
+
+func TestDragLaws(t *testing.T) {
+	m := model()
+	assert.Equal(t, 2.0, m.CalcDrag(2), "drag should be quadratic by default")
+	m.Drag = utils.LinearDrag
+	assert.Equal(t, 1.0, m.CalcDrag(2))
+
+	// a constant force settles at the velocity where the drag balances it
+	state := stationary(1)
+	for i := 0; i < 200; i++ {
+		state = m.GenerateNewState(state, 1, 0)
+	}
+	assert.InDelta(t, 2.0, state.Velocity, 1e-6)
+}
+
+func TestTimeStepScalesRound(t *testing.T) {
+	m := model()
+	m.DragCoefficient = 0
+	m.TimeStep = 2
+	state := m.GenerateNewState(stationary(1), 1, 0.5)
+	assert.Equal(t, 2.0, state.Velocity)
+	assert.InDelta(t, 0.0, state.Position.X, 1e-9)
+	assert.InDelta(t, 4.0, state.Position.Y, 1e-9)
+}
+
+func TestSubStepsConvergeToExactMotion(t *testing.T) {
+	// without drag the distance covered from rest is f/(2m) t^2, which sub-stepping approaches
+	m := model()
+	m.DragCoefficient = 0
+	exact := 0.5
+	previousError := math.Inf(1)
+	for _, steps := range []int{1, 4, 16} {
+		m.SubSteps = steps
+		state := m.GenerateNewState(stationary(1), 1, 0)
+		assert.InDelta(t, 1.0, state.Velocity, 1e-9)
+		stepError := math.Abs(state.Position.X - exact)
+		assert.Less(t, stepError, previousError)
+		previousError = stepError
+	}
+}
+
+func TestDefaultModelMatchesEngine(t *testing.T) {
+	initial := utils.PhysicalState{Position: utils.Coordinates{X: 3, Y: 4}, Velocity: 1, Mass: 5}
+	engine := physics.NewEngine(utils.DefaultSimConfig())
+	assert.Equal(t, physics.GenerateNewState(initial, 2, 0.25), engine.GenerateNewState(initial, 2, 0.25))
+}
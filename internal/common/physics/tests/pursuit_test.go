@@ -0,0 +1,58 @@
+package physics_test
+
+import (
+	"SOMAS2023/internal/common/physics"
+	"SOMAS2023/internal/common/utils"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptStationaryTarget(t *testing.T) {
+	target := utils.Coordinates{X: 5, Y: 5}
+	aim, ok := boundary(utils.Unbounded).InterceptPoint(utils.Coordinates{X: 0, Y: 0}, 1, target, 0, 0)
+	assert.True(t, ok)
+	assert.InDelta(t, target.X, aim.X, 1e-9)
+	assert.InDelta(t, target.Y, aim.Y, 1e-9)
+}
+
+func TestInterceptCrossingTarget(t *testing.T) {
+	// the target crosses in front of the pursuer, which is twice as fast
+	pursuer := utils.Coordinates{X: 0, Y: 0}
+	aim, ok := boundary(utils.Unbounded).InterceptPoint(pursuer, 2, utils.Coordinates{X: 10, Y: 0}, 1, 0.5)
+	assert.True(t, ok)
+	assert.InDelta(t, 10.0, aim.X, 1e-9)
+	// both reach the intercept point at the same time
+	targetTime := aim.Y / 1
+	pursuerTime := math.Hypot(aim.X-pursuer.X, aim.Y-pursuer.Y) / 2
+	assert.InDelta(t, targetTime, pursuerTime, 1e-9)
+	assert.Greater(t, aim.Y, 0.0, "the pursuer should lead the target")
+}
+
+func TestInterceptTooFastTarget(t *testing.T) {
+	target := utils.Coordinates{X: 10, Y: 0}
+	aim, ok := boundary(utils.Unbounded).InterceptPoint(utils.Coordinates{X: 0, Y: 0}, 1, target, 2, 0)
+	assert.False(t, ok, "a target running away faster than the pursuer cannot be caught")
+	assert.Equal(t, target, aim)
+
+	_, ok = boundary(utils.Unbounded).InterceptPoint(utils.Coordinates{X: 0, Y: 0}, 1, target, 2, 1)
+	assert.True(t, ok, "a fast target can be caught when it comes towards the pursuer")
+}
+
+func TestInterceptAcrossTorus(t *testing.T) {
+	// the target is just across the edge of the grid, moving away from the edge
+	aim, ok := boundary(utils.Torus).InterceptPoint(utils.Coordinates{X: 9, Y: 10}, 2, utils.Coordinates{X: 1, Y: 10}, 1, 0)
+	assert.True(t, ok)
+	assert.InDelta(t, 3.0, aim.X, 1e-9)
+	assert.InDelta(t, 10.0, aim.Y, 1e-9)
+}
+
+func TestTurnIsLimited(t *testing.T) {
+	assert.Equal(t, 0.5, physics.Turn(0, 0.5, 1), "turns within the limit are made in full")
+	assert.Equal(t, 0.25, physics.Turn(0, 0.5, 0.25))
+	assert.Equal(t, -0.25, physics.Turn(0, -0.5, 0.25))
+	// the shortest way from 0.9 to -0.9 crosses 1
+	assert.InDelta(t, 1.0, physics.Turn(0.9, -0.9, 0.1), 1e-9)
+	assert.InDelta(t, -0.95, physics.Turn(0.95, -0.9, 0.1), 1e-9)
+}
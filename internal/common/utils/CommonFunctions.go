@@ -1,25 +1,138 @@
 package utils
 
 import (
+	"bytes"
+	"fmt"
 	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
+// NewRand creates the random number generator of a simulation. Every random decision of the server,
+// the objects and the base bikers is drawn from it so that runs with the same seed are reproducible.
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// NewUUID draws a random (version 4) UUID from the given generator
+func NewUUID(rng *rand.Rand) uuid.UUID {
+	return uuid.Must(uuid.NewRandomFromReader(rng))
+}
+
+// SortedIDs returns the keys of a map in ascending order. Go randomises map iteration, so anything
+// that iterates over a map and has an effect on the simulation must go through this.
+func SortedIDs[V any](m map[uuid.UUID]V) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	SortIDs(ids)
+	return ids
+}
+
+// SortIDs sorts a slice of IDs in place in ascending order
+func SortIDs(ids []uuid.UUID) {
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+}
+
 // GenerateRandomCoordinates creates random X and Y coordinates within the grid boundaries.
-func GenerateRandomCoordinates() Coordinates {
+func GenerateRandomCoordinates(rng *rand.Rand, gridWidth float64, gridHeight float64) Coordinates {
 	// Generate random coordinates
 	return Coordinates{
-		X: rand.Float64() * GridWidth,
-		Y: rand.Float64() * GridHeight,
+		X: rng.Float64() * gridWidth,
+		Y: rng.Float64() * gridHeight,
 	}
 }
 
-// GenerateRandomCoordinates creates random X and Y coordinates within the grid boundaries.
-func GenerateRandomColour() Colour {
+// GenerateRandomColour picks one of the colours at random.
+func GenerateRandomColour(rng *rand.Rand) Colour {
 	// Generate a random index between 0 and the number of colours - 1.
-	randomIndex := rand.Intn(int(NumOfColours))
+	randomIndex := rng.Intn(int(NumOfColours))
 	return Colour(randomIndex)
 }
 
-func GenerateRandomFloat(min float64, max float64) float64 {
-	return min + rand.Float64()*(max-min)
+func GenerateRandomFloat(rng *rand.Rand, min float64, max float64) float64 {
+	return min + rng.Float64()*(max-min)
+}
+
+// maxSeedRange is the most seeds a single range of ParseSeeds can list
+const maxSeedRange = 10000
+
+// ParseSeeds reads a comma separated list of seeds and inclusive seed ranges, seeds may be negative ("-5-5")
+func ParseSeeds(list string) ([]int64, error) {
+	seeds := make([]int64, 0)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		// the separator of a range comes after the sign of its start
+		sign := 0
+		if strings.HasPrefix(item, "-") || strings.HasPrefix(item, "+") {
+			sign = 1
+		}
+		from, to, isRange := item, "", false
+		if separator := strings.Index(item[sign:], "-"); separator >= 0 {
+			from, to, isRange = item[:sign+separator], item[sign+separator+1:], true
+		}
+		start, err := strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed %q: %w", item, err)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.ParseInt(to, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid seed range %q: %w", item, err)
+			}
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid seed range %q, it ends before it starts", item)
+		}
+		// the difference is computed unsigned as it may not fit in an int64
+		if uint64(end)-uint64(start) >= maxSeedRange {
+			return nil, fmt.Errorf("invalid seed range %q, a range cannot list more than %d seeds", item, maxSeedRange)
+		}
+		for seed := start; ; seed++ {
+			if seed == 0 {
+				return nil, fmt.Errorf("seed 0 is reserved for unseeded runs")
+			}
+			seeds = append(seeds, seed)
+			if seed == end {
+				break
+			}
+		}
+	}
+	return seeds, nil
+}
+
+// enumNames holds the names the values of an enum are written with in configuration files and game dumps, the
+// enums start at 0 and their values are numbered in order
+type enumNames[T ~int] struct {
+	kind  string   // what the enum is, in error messages
+	names []string // name of every value, in order
+}
+
+func (e enumNames[T]) name(value T) string {
+	if value < 0 || int(value) >= len(e.names) {
+		return "unknown"
+	}
+	return e.names[value]
+}
+
+func (e enumNames[T]) marshal(value T) ([]byte, error) {
+	if value < 0 || int(value) >= len(e.names) {
+		return nil, fmt.Errorf("invalid %s %d", e.kind, int(value))
+	}
+	return []byte(e.names[value]), nil
+}
+
+func (e enumNames[T]) unmarshal(text []byte, value *T) error {
+	index := slices.Index(e.names, string(text))
+	if index < 0 {
+		return fmt.Errorf("unknown %s %q", e.kind, string(text))
+	}
+	*value = T(index)
+	return nil
 }
@@ -1,5 +1,11 @@
 package utils
 
+/*
+The constants below are the default simulation parameters. The server reads its
+parameters from a SimConfig (see SimConfig.go), which is initialised from these
+values and can be overridden from a configuration file.
+*/
+
 /*
 Environment Parameters
 */
@@ -12,6 +18,10 @@ const ReplenishEnergyEveryRound = true
 const ResetPointsEveryRound = true
 const RespawnEveryRound = true
 const RoundIterations = 100
+const BikerAgentCount = 20
+const MegaBikeCount = BikerAgentCount / 4 // Megabikes should have an average of 4 riders
+const LootBoxCount = MegaBikeCount * 3    // 3 available lootboxes per megabike
+const Boundary BoundaryMode = Unbounded   // the rules state there is no physical boundary
 
 /*
 Server Parameters
@@ -25,23 +35,74 @@ Physics Parameters
 const MassBike float64 = 1.0
 const MassBiker float64 = 1.0
 const MassAudi float64 = 10.0
+const MassLootBox float64 = 1.0 // only matters for lootboxes that drift
 
 const BikerMaxForce float64 = 1.0 // The max force a biker can pedal
 const AudiMaxForce float64 = 1.0  // The audi's force is equivalent to that of one biker agent going at maximum speed
 
 const DragCoefficient float64 = 0.5 // Drag coefficient can be optimised in experimentation
+const Drag DragLaw = QuadraticDrag  // the rules state the drag is proportional to the velocity squared
+const MaxVelocity float64 = 5.0     // above the terminal velocity of a full bike under quadratic drag
+const TimeStep float64 = 1.0        // duration of a round
+const SubSteps = 1                  // integration steps per round, more steps give smoother dynamics
+
+const MaxTurnRate float64 = 1.0      // largest turn of a stationary megabike in a round, 1.0 is 180°
+const TurnInertia float64 = 0.05     // how much the momentum (mass * velocity) of a megabike limits its turns
+const TurnMomentumLoss float64 = 0.5 // fraction of the velocity lost when turning 180°
 
 const MovingDepletion float64 = 0.01 // proportionality of energy loss
+const PedalExponent float64 = 1.0    // shape of the pedalling cost curve (pedal ^ exponent * MovingDepletion)
+const LoadCost float64 = 0.0         // energy lost per unit of mass moved by each pedalling rider
+const BrakingCost float64 = 0.0      // energy lost when braking at full force
+const SteeringCost float64 = 0.0     // energy lost when steering by 180°
+const BasalMetabolism float64 = 0.0  // energy lost by every agent every round
 
 const LimboEnergyPenalty float64 = -0.25 // amount of energy lost per round when off a bike
 
 const DeliberativeDemocracyPenalty float64 = 0.05 // amount of energy lost per vote in a deliberative democracy
 const LeadershipDemocracyPenalty float64 = 0.025  // amount of energy lost per vote in a leadership democracy
 
+// amount of energy lost per vote in a council: fewer riders deliberate than in a democracy, but more than in a
+// leadership, so it costs half way between the two
+const CouncilDemocracyPenalty float64 = (DeliberativeDemocracyPenalty + LeadershipDemocracyPenalty) / 2
+
 /*
 Resources - Points and Energy
 */
 const PointsFromSameColouredLootBox = 5.0
+const LootSharing LootSharingRule = EqualSplit // how bikes reaching a lootbox during the same step share it
+
+/*
+Lootbox Spawning
+*/
+const LootBoxPlacement LootBoxPlacementMode = UniformPlacement
+const LootBoxHotspots = 3                // number of hotspots lootboxes cluster around
+const LootBoxHotspotSpread float64 = 5.0 // standard deviation of the distance of lootboxes from their hotspot
+const LootBoxColouring LootBoxColourMode = RandomColours
+const LootBoxResources ResourceDistribution = UniformResources
+const LootBoxMinResources float64 = 5.0
+const LootBoxMaxResources float64 = 8.0
+const LootBoxRichRegions = 0 // number of regions where lootboxes hold more resources
+const LootBoxRichRadius float64 = 10.0
+const LootBoxRichMultiplier float64 = 2.0 // resources of lootboxes spawned in a rich region are multiplied by this
+const LootBoxScarcityPeriod = 0           // length in rounds of the abundance and scarcity cycle, 0 disables it
+const LootBoxScarcityRounds = 0           // rounds at the end of each cycle during which lootboxes are not replenished
+const LootBoxDecay float64 = 0.0          // fraction of its resources a lootbox loses every round
+const LootBoxLifetime = 0                 // rounds a lootbox lasts before it expires, 0 for lootboxes that never expire
+const LootBoxDriftForce float64 = 0.0     // force pushing lootboxes along a random direction, 0 for static lootboxes
+
+/*
+Lootbox Observation
+*/
+const LootBoxResourceObservation ObservationMode = ExactObservation
+const LootBoxColourObservation ObservationMode = ExactObservation
+const LootBoxRevealRadius float64 = 10.0  // riders see lootboxes closer than this to their bike as they are
+const LootBoxResourceNoise float64 = 0.25 // standard deviation of noisy resources, relative to the actual resources
+const LootBoxColourNoise float64 = 0.5    // chance of a noisy colour being a random one
+
+// resources and colour agents see for a lootbox they cannot make out
+const HiddenResources float64 = -1.0
+const HiddenColour Colour = NumOfColours
 
 /*
 Audi Behavior
@@ -49,14 +110,51 @@ Audi Behavior
 const AudiTargetsEmptyMegaBike bool = false
 const AudiOnlyTargetsStationaryMegaBike bool = true // if false, targeting slowest
 const AudiRemovesMegaBike bool = false
+const AudiCount = 1
+const AudiStrategy AudiTargetingStrategy = TargetSlowest
+const AudiPursuit PursuitMode = DirectPursuit
+const AudiMaxTurnRate float64 = 1.0 // largest turn of the audi in a round, 1.0 is 180° (i.e. no limit)
+const AudiCollision AudiCollisionOutcome = KillAllRiders
+const AudiKillProbability float64 = 0.5 // chance of each rider dying when the audi kills random riders
+const AudiDamage float64 = 0.5          // energy taken from each rider per unit of speed of the audi over the bike
+const AudiShieldCost float64 = 0.0      // shield energy a bike needs to block a hit, 0 disables shields
+const AudiCooldown = 0                  // rounds the audi stays idle after a hit
+const AudiRespawns bool = false         // the audi moves to a random position after a hit
+
+/*
+Governance Referendums
+*/
+const ReferendumPeriod = 0                        // rounds between governance referendums on every bike, 0 disables them
+const ReferendumPetition float64 = 0.0            // fraction of the riders of a bike that can call a referendum, 0 disables petitions
+const ReferendumSupermajority float64 = 2.0 / 3.0 // share of the votes a governance needs to replace the current one
+
+/*
+Bike Constitution, the rules every bike starts with (riders can amend them)
+*/
+const KickoutThreshold float64 = 0.5                   // a rider is kicked out with more than this share of the votes of the riders
+const AdmissionQuorum float64 = 0.5                    // an agent joins a bike with more than this share of the votes of the riders
+const LootAllocation AllocationMethod = MeanAllocation // how the allocation votes of the riders are combined
+const RulerTerm = 0                                    // rounds a ruler serves before a new election, 0 for no elections
+const AmendmentSupermajority float64 = 2.0 / 3.0       // share of the votes an amendment needs to pass
+const TermLimit = 0                                    // consecutive terms a ruler or council member can serve, 0 for no limit
+const ImpeachmentSupermajority float64 = 2.0 / 3.0     // share of the votes of the other riders needed to impeach a ruler
+const CouncilSize = 3                                  // riders elected to the council of a bike governed by a council
+
+/*
+Graduated Sanctions, the sanctions of an agent escalate with its offences (see SimConfig.SanctionOf)
+*/
+const SanctionFine float64 = 0.1       // energy a fined rider pays into the fine pool of its bike
+const SanctionDuration = 3             // rounds a vote weight reduction or a loot exclusion lasts
+const SanctionVoteWeight float64 = 0.5 // factor applied to the weight of the votes of a rider whose vote weight is reduced
+const SanctionAmnesty = 0              // rounds after which an offence is forgotten, 0 never forgets
 
 /*
 Voting Method Choice
 */
-type voteMethods int
+type VoteMethod int
 
 const (
-	PLURALITY voteMethods = iota
+	PLURALITY VoteMethod = iota
 	RUNOFF
 	BORDACOUNT
 	INSTANTRUNOFF
@@ -64,4 +162,348 @@ const (
 	COPELANDSCORING
 )
 
-const VoteAction voteMethods = PLURALITY
+const VoteAction VoteMethod = PLURALITY
+
+var voteMethodNames = enumNames[VoteMethod]{kind: "voting method", names: []string{"plurality", "runoff", "borda_count", "instant_runoff", "approval", "copeland_scoring"}}
+
+func (v VoteMethod) String() string {
+	return voteMethodNames.name(v)
+}
+
+// MarshalText allows the voting method to be written by name in configuration files
+func (v VoteMethod) MarshalText() ([]byte, error) {
+	return voteMethodNames.marshal(v)
+}
+
+// UnmarshalText allows the voting method to be read by name from configuration files
+func (v *VoteMethod) UnmarshalText(text []byte) error {
+	return voteMethodNames.unmarshal(text, v)
+}
+
+/*
+World Boundary
+*/
+type BoundaryMode int
+
+const (
+	Unbounded BoundaryMode = iota // objects can drift off the grid
+	Clamp                         // objects stop at the edges of the grid
+	Reflect                       // objects bounce off the edges of the grid
+	Torus                         // objects leaving the grid re-enter it on the opposite side
+)
+
+var boundaryModeNames = enumNames[BoundaryMode]{kind: "boundary mode", names: []string{"unbounded", "clamp", "reflect", "torus"}}
+
+func (b BoundaryMode) String() string {
+	return boundaryModeNames.name(b)
+}
+
+// MarshalText allows the boundary mode to be written by name in configuration files
+func (b BoundaryMode) MarshalText() ([]byte, error) {
+	return boundaryModeNames.marshal(b)
+}
+
+// UnmarshalText allows the boundary mode to be read by name from configuration files
+func (b *BoundaryMode) UnmarshalText(text []byte) error {
+	return boundaryModeNames.unmarshal(text, b)
+}
+
+/*
+Drag Law
+*/
+type DragLaw int
+
+const (
+	QuadraticDrag DragLaw = iota // drag proportional to the velocity squared
+	LinearDrag                   // drag proportional to the velocity
+)
+
+var dragLawNames = enumNames[DragLaw]{kind: "drag law", names: []string{"quadratic", "linear"}}
+
+func (d DragLaw) String() string {
+	return dragLawNames.name(d)
+}
+
+// MarshalText allows the drag law to be written by name in configuration files
+func (d DragLaw) MarshalText() ([]byte, error) {
+	return dragLawNames.marshal(d)
+}
+
+// UnmarshalText allows the drag law to be read by name from configuration files
+func (d *DragLaw) UnmarshalText(text []byte) error {
+	return dragLawNames.unmarshal(text, d)
+}
+
+/*
+Audi Targeting
+*/
+type AudiTargetingStrategy int
+
+const (
+	TargetSlowest       AudiTargetingStrategy = iota // the slowest bike, then the nearest
+	TargetNearest                                    // the nearest bike
+	TargetRichest                                    // the bike whose riders have the most points
+	TargetMostPopulated                              // the bike with the most riders
+	TargetRandom                                     // a random bike, until it is reached or cannot be targeted
+	TargetRoundRobin                                 // every bike in turn, moving on when the target is reached or cannot be targeted
+)
+
+var audiTargetingStrategyNames = enumNames[AudiTargetingStrategy]{kind: "audi strategy", names: []string{"slowest", "nearest", "richest", "most_populated", "random", "round_robin"}}
+
+func (a AudiTargetingStrategy) String() string {
+	return audiTargetingStrategyNames.name(a)
+}
+
+// MarshalText allows the audi strategy to be written by name in configuration files
+func (a AudiTargetingStrategy) MarshalText() ([]byte, error) {
+	return audiTargetingStrategyNames.marshal(a)
+}
+
+// UnmarshalText allows the audi strategy to be read by name from configuration files
+func (a *AudiTargetingStrategy) UnmarshalText(text []byte) error {
+	return audiTargetingStrategyNames.unmarshal(text, a)
+}
+
+/*
+Audi Pursuit
+*/
+type PursuitMode int
+
+const (
+	DirectPursuit    PursuitMode = iota // head for the current position of the target
+	InterceptPursuit                    // head for where the target will be when the audi catches it
+)
+
+var pursuitModeNames = enumNames[PursuitMode]{kind: "pursuit mode", names: []string{"direct", "intercept"}}
+
+func (p PursuitMode) String() string {
+	return pursuitModeNames.name(p)
+}
+
+// MarshalText allows the pursuit mode to be written by name in configuration files
+func (p PursuitMode) MarshalText() ([]byte, error) {
+	return pursuitModeNames.marshal(p)
+}
+
+// UnmarshalText allows the pursuit mode to be read by name from configuration files
+func (p *PursuitMode) UnmarshalText(text []byte) error {
+	return pursuitModeNames.unmarshal(text, p)
+}
+
+/*
+Audi Collision
+*/
+type AudiCollisionOutcome int
+
+const (
+	KillAllRiders    AudiCollisionOutcome = iota // every rider of the bike dies
+	KillRandomRiders                             // each rider dies with a set probability
+	DamageRiders                                 // riders lose energy, more when the audi is much faster than the bike
+)
+
+var audiCollisionOutcomeNames = enumNames[AudiCollisionOutcome]{kind: "audi collision outcome", names: []string{"kill_all", "kill_random", "damage"}}
+
+func (a AudiCollisionOutcome) String() string {
+	return audiCollisionOutcomeNames.name(a)
+}
+
+// MarshalText allows the collision outcome to be written by name in configuration files
+func (a AudiCollisionOutcome) MarshalText() ([]byte, error) {
+	return audiCollisionOutcomeNames.marshal(a)
+}
+
+// UnmarshalText allows the collision outcome to be read by name from configuration files
+func (a *AudiCollisionOutcome) UnmarshalText(text []byte) error {
+	return audiCollisionOutcomeNames.unmarshal(text, a)
+}
+
+/*
+Lootbox Placement
+*/
+type LootBoxPlacementMode int
+
+const (
+	UniformPlacement LootBoxPlacementMode = iota // anywhere on the grid
+	HotspotPlacement                             // clustered around hotspots
+)
+
+var lootBoxPlacementModeNames = enumNames[LootBoxPlacementMode]{kind: "lootbox placement", names: []string{"uniform", "hotspots"}}
+
+func (l LootBoxPlacementMode) String() string {
+	return lootBoxPlacementModeNames.name(l)
+}
+
+// MarshalText allows the lootbox placement to be written by name in configuration files
+func (l LootBoxPlacementMode) MarshalText() ([]byte, error) {
+	return lootBoxPlacementModeNames.marshal(l)
+}
+
+// UnmarshalText allows the lootbox placement to be read by name from configuration files
+func (l *LootBoxPlacementMode) UnmarshalText(text []byte) error {
+	return lootBoxPlacementModeNames.unmarshal(text, l)
+}
+
+/*
+Lootbox Colours
+*/
+type LootBoxColourMode int
+
+const (
+	RandomColours LootBoxColourMode = iota // any colour anywhere
+	ZonedColours                           // the grid is split into vertical strips, one per colour
+)
+
+var lootBoxColourModeNames = enumNames[LootBoxColourMode]{kind: "lootbox colouring", names: []string{"random", "zoned"}}
+
+func (l LootBoxColourMode) String() string {
+	return lootBoxColourModeNames.name(l)
+}
+
+// MarshalText allows the lootbox colouring to be written by name in configuration files
+func (l LootBoxColourMode) MarshalText() ([]byte, error) {
+	return lootBoxColourModeNames.marshal(l)
+}
+
+// UnmarshalText allows the lootbox colouring to be read by name from configuration files
+func (l *LootBoxColourMode) UnmarshalText(text []byte) error {
+	return lootBoxColourModeNames.unmarshal(text, l)
+}
+
+/*
+Lootbox Resources
+*/
+type ResourceDistribution int
+
+const (
+	UniformResources     ResourceDistribution = iota // anywhere between the minimum and maximum
+	NormalResources                                  // around the middle of the range, within the minimum and maximum
+	ExponentialResources                             // mostly close to the minimum with a long tail, averaging the middle of the range
+)
+
+var resourceDistributionNames = enumNames[ResourceDistribution]{kind: "resource distribution", names: []string{"uniform", "normal", "exponential"}}
+
+func (r ResourceDistribution) String() string {
+	return resourceDistributionNames.name(r)
+}
+
+// MarshalText allows the resource distribution to be written by name in configuration files
+func (r ResourceDistribution) MarshalText() ([]byte, error) {
+	return resourceDistributionNames.marshal(r)
+}
+
+// UnmarshalText allows the resource distribution to be read by name from configuration files
+func (r *ResourceDistribution) UnmarshalText(text []byte) error {
+	return resourceDistributionNames.unmarshal(text, r)
+}
+
+/*
+Loot Sharing
+*/
+type LootSharingRule int
+
+const (
+	EqualSplit   LootSharingRule = iota // the bikes arriving first split the lootbox equally
+	FirstArrival                        // the bike arriving first takes the whole lootbox
+	RiderShare                          // every bike reaching the lootbox gets a share proportional to its riders
+	EffortShare                         // every bike reaching the lootbox gets a share proportional to the pedalling of its riders
+	Bargaining                          // the bikes reaching the lootbox claim a share of it, and get nothing if their claims are incompatible
+)
+
+var lootSharingRuleNames = enumNames[LootSharingRule]{kind: "loot sharing rule", names: []string{"equal", "first_arrival", "riders", "effort", "bargaining"}}
+
+func (l LootSharingRule) String() string {
+	return lootSharingRuleNames.name(l)
+}
+
+// MarshalText allows the loot sharing rule to be written by name in configuration files
+func (l LootSharingRule) MarshalText() ([]byte, error) {
+	return lootSharingRuleNames.marshal(l)
+}
+
+// UnmarshalText allows the loot sharing rule to be read by name from configuration files
+func (l *LootSharingRule) UnmarshalText(text []byte) error {
+	return lootSharingRuleNames.unmarshal(text, l)
+}
+
+/*
+Lootbox Observation
+*/
+type ObservationMode int
+
+const (
+	ExactObservation  ObservationMode = iota // agents see the actual value
+	HiddenObservation                        // agents see nothing until their bike is within the reveal radius
+	NoisyObservation                         // agents see a noisy value until their bike is within the reveal radius
+)
+
+var observationModeNames = enumNames[ObservationMode]{kind: "observation mode", names: []string{"exact", "hidden", "noisy"}}
+
+func (o ObservationMode) String() string {
+	return observationModeNames.name(o)
+}
+
+// MarshalText allows the observation mode to be written by name in configuration files
+func (o ObservationMode) MarshalText() ([]byte, error) {
+	return observationModeNames.marshal(o)
+}
+
+// UnmarshalText allows the observation mode to be read by name from configuration files
+func (o *ObservationMode) UnmarshalText(text []byte) error {
+	return observationModeNames.unmarshal(text, o)
+}
+
+/*
+Loot Allocation
+*/
+type AllocationMethod int
+
+const (
+	MeanAllocation   AllocationMethod = iota // every rider gets the (weighted) mean of the shares the riders voted for them
+	MedianAllocation                         // every rider gets the (weighted) median of the shares the riders voted for them
+	EqualAllocation                          // the riders split the loot equally whatever they vote
+)
+
+var allocationMethodNames = enumNames[AllocationMethod]{kind: "allocation method", names: []string{"mean", "median", "equal"}}
+
+func (a AllocationMethod) String() string {
+	return allocationMethodNames.name(a)
+}
+
+// MarshalText allows the allocation method to be written by name in configuration files
+func (a AllocationMethod) MarshalText() ([]byte, error) {
+	return allocationMethodNames.marshal(a)
+}
+
+// UnmarshalText allows the allocation method to be read by name from configuration files
+func (a *AllocationMethod) UnmarshalText(text []byte) error {
+	return allocationMethodNames.unmarshal(text, a)
+}
+
+/*
+Graduated Sanctions
+*/
+type SanctionType int
+
+const (
+	WarningSanction       SanctionType = iota // the offence is recorded, nothing else happens
+	FineSanction                              // the rider pays a fine into the fine pool of its bike
+	VoteWeightSanction                        // the weight of the votes of the rider is reduced for a while, in every vote
+	LootExclusionSanction                     // the rider gets no loot for a while, its share goes to the fine pool
+	KickoutSanction                           // the rider is kicked out of the bike
+)
+
+var sanctionTypeNames = enumNames[SanctionType]{kind: "sanction", names: []string{"warning", "fine", "vote_weight", "loot_exclusion", "kickout"}}
+
+func (t SanctionType) String() string {
+	return sanctionTypeNames.name(t)
+}
+
+// MarshalText allows the sanction to be written by name in configuration files and game dumps
+func (t SanctionType) MarshalText() ([]byte, error) {
+	return sanctionTypeNames.marshal(t)
+}
+
+// UnmarshalText allows the sanction to be read by name from configuration files
+func (t *SanctionType) UnmarshalText(text []byte) error {
+	return sanctionTypeNames.unmarshal(text, t)
+}
@@ -75,6 +75,7 @@ const (
 	Democracy Governance = iota
 	Leadership
 	Dictatorship
+	Council
 	Invalid
 )
 
@@ -0,0 +1,496 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SimConfig holds every tunable parameter of a simulation run. It is created by the
+// server at initialisation and passed to the physics engine, the objects and the voting
+// engine, so that experiments can be run without recompiling.
+type SimConfig struct {
+	// Environment
+	GridHeight                float64      `json:"grid_height" yaml:"grid_height"`
+	GridWidth                 float64      `json:"grid_width" yaml:"grid_width"`
+	Boundary                  BoundaryMode `json:"boundary" yaml:"boundary"` // what happens to objects reaching the edge of the grid
+	CollisionThreshold        float64      `json:"collision_threshold" yaml:"collision_threshold"`
+	BikersOnBike              int          `json:"bikers_on_bike" yaml:"bikers_on_bike"`
+	ReplenishEnergyEveryRound bool         `json:"replenish_energy_every_round" yaml:"replenish_energy_every_round"`
+	ResetPointsEveryRound     bool         `json:"reset_points_every_round" yaml:"reset_points_every_round"`
+	RespawnEveryRound         bool         `json:"respawn_every_round" yaml:"respawn_every_round"`
+	RoundIterations           int          `json:"round_iterations" yaml:"round_iterations"`
+
+	// Server
+	BikerAgentCount int `json:"biker_agent_count" yaml:"biker_agent_count"`
+	// TeamCounts gives the number of agents spawned for each team (by registered name, e.g. "team1"),
+	// teams that are not listed are not spawned. If it is empty BikerAgentCount is split evenly.
+	TeamCounts         map[string]int `json:"team_counts,omitempty" yaml:"team_counts,omitempty"`
+	MegaBikeCount      int            `json:"mega_bike_count" yaml:"mega_bike_count"`
+	LootBoxCount       int            `json:"loot_box_count" yaml:"loot_box_count"`
+	ReplenishLootBoxes bool           `json:"replenish_loot_boxes" yaml:"replenish_loot_boxes"`
+	ReplenishMegaBikes bool           `json:"replenish_mega_bikes" yaml:"replenish_mega_bikes"`
+
+	// Physics
+	MassBike        float64 `json:"mass_bike" yaml:"mass_bike"`
+	MassBiker       float64 `json:"mass_biker" yaml:"mass_biker"`
+	MassAudi        float64 `json:"mass_audi" yaml:"mass_audi"`
+	MassLootBox     float64 `json:"mass_loot_box" yaml:"mass_loot_box"`
+	BikerMaxForce   float64 `json:"biker_max_force" yaml:"biker_max_force"`
+	AudiMaxForce    float64 `json:"audi_max_force" yaml:"audi_max_force"`
+	DragCoefficient float64 `json:"drag_coefficient" yaml:"drag_coefficient"`
+	Drag            DragLaw `json:"drag" yaml:"drag"`
+	MaxVelocity     float64 `json:"max_velocity" yaml:"max_velocity"`
+	TimeStep        float64 `json:"time_step" yaml:"time_step"` // duration of a round
+	SubSteps        int     `json:"sub_steps" yaml:"sub_steps"` // integration steps the physics takes per round
+	// Megabike turning: the largest turn in a round is max_turn_rate / (1 + turn_inertia * mass * velocity)
+	// and a turn costs turn_momentum_loss * |turn| of the velocity
+	MaxTurnRate      float64 `json:"max_turn_rate" yaml:"max_turn_rate"`
+	TurnInertia      float64 `json:"turn_inertia" yaml:"turn_inertia"`
+	TurnMomentumLoss float64 `json:"turn_momentum_loss" yaml:"turn_momentum_loss"`
+
+	// Energy
+	MovingDepletion              float64 `json:"moving_depletion" yaml:"moving_depletion"`
+	PedalExponent                float64 `json:"pedal_exponent" yaml:"pedal_exponent"`
+	LoadCost                     float64 `json:"load_cost" yaml:"load_cost"`
+	BrakingCost                  float64 `json:"braking_cost" yaml:"braking_cost"`
+	SteeringCost                 float64 `json:"steering_cost" yaml:"steering_cost"`
+	BasalMetabolism              float64 `json:"basal_metabolism" yaml:"basal_metabolism"`
+	LimboEnergyPenalty           float64 `json:"limbo_energy_penalty" yaml:"limbo_energy_penalty"`
+	DeliberativeDemocracyPenalty float64 `json:"deliberative_democracy_penalty" yaml:"deliberative_democracy_penalty"`
+	LeadershipDemocracyPenalty   float64 `json:"leadership_democracy_penalty" yaml:"leadership_democracy_penalty"`
+	CouncilDemocracyPenalty      float64 `json:"council_democracy_penalty" yaml:"council_democracy_penalty"`
+
+	// Resources
+	PointsFromSameColouredLootBox int             `json:"points_from_same_coloured_loot_box" yaml:"points_from_same_coloured_loot_box"`
+	LootSharing                   LootSharingRule `json:"loot_sharing" yaml:"loot_sharing"`
+
+	// Lootbox spawning, see objects.LootBoxSpawnPolicy
+	LootBoxPlacement      LootBoxPlacementMode `json:"loot_box_placement" yaml:"loot_box_placement"`
+	LootBoxHotspots       int                  `json:"loot_box_hotspots" yaml:"loot_box_hotspots"`
+	LootBoxHotspotSpread  float64              `json:"loot_box_hotspot_spread" yaml:"loot_box_hotspot_spread"`
+	LootBoxColouring      LootBoxColourMode    `json:"loot_box_colouring" yaml:"loot_box_colouring"`
+	LootBoxResources      ResourceDistribution `json:"loot_box_resources" yaml:"loot_box_resources"`
+	LootBoxMinResources   float64              `json:"loot_box_min_resources" yaml:"loot_box_min_resources"`
+	LootBoxMaxResources   float64              `json:"loot_box_max_resources" yaml:"loot_box_max_resources"`
+	LootBoxRichRegions    int                  `json:"loot_box_rich_regions" yaml:"loot_box_rich_regions"`
+	LootBoxRichRadius     float64              `json:"loot_box_rich_radius" yaml:"loot_box_rich_radius"`
+	LootBoxRichMultiplier float64              `json:"loot_box_rich_multiplier" yaml:"loot_box_rich_multiplier"`
+	LootBoxScarcityPeriod int                  `json:"loot_box_scarcity_period" yaml:"loot_box_scarcity_period"`
+	LootBoxScarcityRounds int                  `json:"loot_box_scarcity_rounds" yaml:"loot_box_scarcity_rounds"`
+	// Lootbox lifecycle, see objects.LootBox
+	LootBoxDecay      float64 `json:"loot_box_decay" yaml:"loot_box_decay"`
+	LootBoxLifetime   int     `json:"loot_box_lifetime" yaml:"loot_box_lifetime"`
+	LootBoxDriftForce float64 `json:"loot_box_drift_force" yaml:"loot_box_drift_force"`
+	// Lootbox observation, what agents see of the lootboxes far from their bike
+	LootBoxResourceObservation ObservationMode `json:"loot_box_resource_observation" yaml:"loot_box_resource_observation"`
+	LootBoxColourObservation   ObservationMode `json:"loot_box_colour_observation" yaml:"loot_box_colour_observation"`
+	LootBoxRevealRadius        float64         `json:"loot_box_reveal_radius" yaml:"loot_box_reveal_radius"`
+	LootBoxResourceNoise       float64         `json:"loot_box_resource_noise" yaml:"loot_box_resource_noise"`
+	LootBoxColourNoise         float64         `json:"loot_box_colour_noise" yaml:"loot_box_colour_noise"`
+
+	// Audi
+	AudiCount                         int  `json:"audi_count" yaml:"audi_count"`
+	AudiTargetsEmptyMegaBike          bool `json:"audi_targets_empty_mega_bike" yaml:"audi_targets_empty_mega_bike"`
+	AudiOnlyTargetsStationaryMegaBike bool `json:"audi_only_targets_stationary_mega_bike" yaml:"audi_only_targets_stationary_mega_bike"`
+	AudiRemovesMegaBike               bool `json:"audi_removes_mega_bike" yaml:"audi_removes_mega_bike"`
+	// how the audi picks the bike it chases (amongst the non empty bikes, unless audi_targets_empty_mega_bike)
+	AudiStrategy AudiTargetingStrategy `json:"audi_strategy" yaml:"audi_strategy"`
+	// AudiStrategies gives the strategy of each audi in turn, audis that are not listed use AudiStrategy
+	AudiStrategies  []AudiTargetingStrategy `json:"audi_strategies,omitempty" yaml:"audi_strategies,omitempty"`
+	AudiPursuit     PursuitMode             `json:"audi_pursuit" yaml:"audi_pursuit"` // how the audi steers towards its target
+	AudiMaxTurnRate float64                 `json:"audi_max_turn_rate" yaml:"audi_max_turn_rate"`
+	// what happens when the audi hits a bike, see AudiCollisionOutcome
+	AudiCollision       AudiCollisionOutcome `json:"audi_collision" yaml:"audi_collision"`
+	AudiKillProbability float64              `json:"audi_kill_probability" yaml:"audi_kill_probability"`
+	AudiDamage          float64              `json:"audi_damage" yaml:"audi_damage"`
+	AudiShieldCost      float64              `json:"audi_shield_cost" yaml:"audi_shield_cost"`
+	AudiCooldown        int                  `json:"audi_cooldown" yaml:"audi_cooldown"`
+	AudiRespawns        bool                 `json:"audi_respawns" yaml:"audi_respawns"`
+
+	// Voting
+	VoteAction VoteMethod `json:"vote_action" yaml:"vote_action"`
+	// Governance referendums held on the bikes during a game, see Server.RunReferendums
+	ReferendumPeriod        int     `json:"referendum_period" yaml:"referendum_period"`
+	ReferendumPetition      float64 `json:"referendum_petition" yaml:"referendum_petition"`
+	ReferendumSupermajority float64 `json:"referendum_supermajority" yaml:"referendum_supermajority"`
+	// Rules every bike starts with, see objects.Constitution (vote_action and referendum_supermajority also are)
+	KickoutThreshold         float64          `json:"kickout_threshold" yaml:"kickout_threshold"`
+	AdmissionQuorum          float64          `json:"admission_quorum" yaml:"admission_quorum"`
+	LootAllocation           AllocationMethod `json:"loot_allocation" yaml:"loot_allocation"`
+	RulerTerm                int              `json:"ruler_term" yaml:"ruler_term"`
+	AmendmentSupermajority   float64          `json:"amendment_supermajority" yaml:"amendment_supermajority"`
+	TermLimit                int              `json:"term_limit" yaml:"term_limit"`
+	ImpeachmentSupermajority float64          `json:"impeachment_supermajority" yaml:"impeachment_supermajority"`
+	CouncilSize              int              `json:"council_size" yaml:"council_size"`
+	// Graduated sanctions riders impose on each other, see Server.RunSanctions. SanctionLadder gives the sanction
+	// of each offence in turn, see SanctionOf
+	SanctionLadder     []SanctionType `json:"sanction_ladder,omitempty" yaml:"sanction_ladder,omitempty"`
+	SanctionFine       float64        `json:"sanction_fine" yaml:"sanction_fine"`
+	SanctionDuration   int            `json:"sanction_duration" yaml:"sanction_duration"`
+	SanctionVoteWeight float64        `json:"sanction_vote_weight" yaml:"sanction_vote_weight"`
+	SanctionAmnesty    int            `json:"sanction_amnesty" yaml:"sanction_amnesty"`
+
+	// Seed of the random number generator. Runs with the same seed and parameters produce the same
+	// game. If it is 0 the server picks a seed at random (and stores it in its config).
+	Seed int64 `json:"seed" yaml:"seed"`
+}
+
+// DefaultSimConfig returns the configuration matching the constants in CommonParameters.go
+func DefaultSimConfig() SimConfig {
+	return SimConfig{
+		GridHeight:                        GridHeight,
+		GridWidth:                         GridWidth,
+		Boundary:                          Boundary,
+		CollisionThreshold:                CollisionThreshold,
+		BikersOnBike:                      BikersOnBike,
+		ReplenishEnergyEveryRound:         ReplenishEnergyEveryRound,
+		ResetPointsEveryRound:             ResetPointsEveryRound,
+		RespawnEveryRound:                 RespawnEveryRound,
+		RoundIterations:                   RoundIterations,
+		BikerAgentCount:                   BikerAgentCount,
+		MegaBikeCount:                     MegaBikeCount,
+		LootBoxCount:                      LootBoxCount,
+		ReplenishLootBoxes:                ReplenishLootBoxes,
+		ReplenishMegaBikes:                ReplenishMegaBikes,
+		MassBike:                          MassBike,
+		MassBiker:                         MassBiker,
+		MassAudi:                          MassAudi,
+		MassLootBox:                       MassLootBox,
+		BikerMaxForce:                     BikerMaxForce,
+		AudiMaxForce:                      AudiMaxForce,
+		DragCoefficient:                   DragCoefficient,
+		Drag:                              Drag,
+		MaxVelocity:                       MaxVelocity,
+		TimeStep:                          TimeStep,
+		SubSteps:                          SubSteps,
+		MaxTurnRate:                       MaxTurnRate,
+		TurnInertia:                       TurnInertia,
+		TurnMomentumLoss:                  TurnMomentumLoss,
+		MovingDepletion:                   MovingDepletion,
+		PedalExponent:                     PedalExponent,
+		LoadCost:                          LoadCost,
+		BrakingCost:                       BrakingCost,
+		SteeringCost:                      SteeringCost,
+		BasalMetabolism:                   BasalMetabolism,
+		LimboEnergyPenalty:                LimboEnergyPenalty,
+		DeliberativeDemocracyPenalty:      DeliberativeDemocracyPenalty,
+		LeadershipDemocracyPenalty:        LeadershipDemocracyPenalty,
+		CouncilDemocracyPenalty:           CouncilDemocracyPenalty,
+		PointsFromSameColouredLootBox:     PointsFromSameColouredLootBox,
+		LootSharing:                       LootSharing,
+		LootBoxPlacement:                  LootBoxPlacement,
+		LootBoxHotspots:                   LootBoxHotspots,
+		LootBoxHotspotSpread:              LootBoxHotspotSpread,
+		LootBoxColouring:                  LootBoxColouring,
+		LootBoxResources:                  LootBoxResources,
+		LootBoxMinResources:               LootBoxMinResources,
+		LootBoxMaxResources:               LootBoxMaxResources,
+		LootBoxRichRegions:                LootBoxRichRegions,
+		LootBoxRichRadius:                 LootBoxRichRadius,
+		LootBoxRichMultiplier:             LootBoxRichMultiplier,
+		LootBoxScarcityPeriod:             LootBoxScarcityPeriod,
+		LootBoxScarcityRounds:             LootBoxScarcityRounds,
+		LootBoxDecay:                      LootBoxDecay,
+		LootBoxLifetime:                   LootBoxLifetime,
+		LootBoxDriftForce:                 LootBoxDriftForce,
+		LootBoxResourceObservation:        LootBoxResourceObservation,
+		LootBoxColourObservation:          LootBoxColourObservation,
+		LootBoxRevealRadius:               LootBoxRevealRadius,
+		LootBoxResourceNoise:              LootBoxResourceNoise,
+		LootBoxColourNoise:                LootBoxColourNoise,
+		AudiCount:                         AudiCount,
+		AudiTargetsEmptyMegaBike:          AudiTargetsEmptyMegaBike,
+		AudiOnlyTargetsStationaryMegaBike: AudiOnlyTargetsStationaryMegaBike,
+		AudiRemovesMegaBike:               AudiRemovesMegaBike,
+		AudiStrategy:                      AudiStrategy,
+		AudiPursuit:                       AudiPursuit,
+		AudiMaxTurnRate:                   AudiMaxTurnRate,
+		AudiCollision:                     AudiCollision,
+		AudiKillProbability:               AudiKillProbability,
+		AudiDamage:                        AudiDamage,
+		AudiShieldCost:                    AudiShieldCost,
+		AudiCooldown:                      AudiCooldown,
+		AudiRespawns:                      AudiRespawns,
+		VoteAction:                        VoteAction,
+		ReferendumPeriod:                  ReferendumPeriod,
+		ReferendumPetition:                ReferendumPetition,
+		ReferendumSupermajority:           ReferendumSupermajority,
+		KickoutThreshold:                  KickoutThreshold,
+		AdmissionQuorum:                   AdmissionQuorum,
+		LootAllocation:                    LootAllocation,
+		RulerTerm:                         RulerTerm,
+		AmendmentSupermajority:            AmendmentSupermajority,
+		TermLimit:                         TermLimit,
+		ImpeachmentSupermajority:          ImpeachmentSupermajority,
+		CouncilSize:                       CouncilSize,
+		SanctionFine:                      SanctionFine,
+		SanctionDuration:                  SanctionDuration,
+		SanctionVoteWeight:                SanctionVoteWeight,
+		SanctionAmnesty:                   SanctionAmnesty,
+	}
+}
+
+// LoadSimConfig reads a YAML or JSON configuration file (chosen by extension). Any parameter
+// missing from the file keeps its default value. The resulting configuration is validated.
+func LoadSimConfig(path string) (SimConfig, error) {
+	config := DefaultSimConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config); err != nil {
+			return config, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil {
+			return config, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	default:
+		return config, fmt.Errorf("unsupported config file extension %q", filepath.Ext(path))
+	}
+
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// WithParameters returns a copy of the configuration with some parameters overridden. The
+// parameters are keyed by their YAML names (e.g. "limbo_energy_penalty": -0.5) and the resulting
+// configuration is validated.
+func (c SimConfig) WithParameters(parameters map[string]any) (SimConfig, error) {
+	config := c
+	data, err := yaml.Marshal(parameters)
+	if err != nil {
+		return c, fmt.Errorf("encoding parameters: %w", err)
+	}
+	// decoding into a map merges the keys, team counts are replaced instead
+	if _, ok := parameters["team_counts"]; ok {
+		config.TeamCounts = nil
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return c, fmt.Errorf("applying parameters: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return c, fmt.Errorf("invalid parameters %v: %w", parameters, err)
+	}
+	return config, nil
+}
+
+// AgentCount returns the total number of agents spawned at the start of the simulation
+func (c SimConfig) AgentCount() int {
+	if len(c.TeamCounts) == 0 {
+		return c.BikerAgentCount
+	}
+	count := 0
+	for _, teamCount := range c.TeamCounts {
+		count += teamCount
+	}
+	return count
+}
+
+// SanctionOf returns the sanction for the n-th offence of an agent (counting from 1), offences past the end of
+// SanctionLadder get its last sanction. Without a ladder the sanctions escalate from a warning to a kickout.
+func (c SimConfig) SanctionOf(offence int) SanctionType {
+	if len(c.SanctionLadder) == 0 {
+		return SanctionType(min(max(offence, 1)-1, int(KickoutSanction)))
+	}
+	return c.SanctionLadder[min(max(offence, 1), len(c.SanctionLadder))-1]
+}
+
+// AudiStrategyOf returns the targeting strategy of the i-th audi
+func (c SimConfig) AudiStrategyOf(i int) AudiTargetingStrategy {
+	if i < len(c.AudiStrategies) {
+		return c.AudiStrategies[i]
+	}
+	return c.AudiStrategy
+}
+
+// Validate checks that the configuration describes a runnable simulation
+func (c SimConfig) Validate() error {
+	var errs []error
+	if c.GridHeight <= 0 || c.GridWidth <= 0 {
+		errs = append(errs, errors.New("grid dimensions must be positive"))
+	}
+	if c.Boundary < Unbounded || c.Boundary > Torus {
+		errs = append(errs, fmt.Errorf("invalid boundary %d", int(c.Boundary)))
+	}
+	if c.CollisionThreshold <= 0 {
+		errs = append(errs, errors.New("collision_threshold must be positive"))
+	}
+	if c.BikersOnBike <= 0 {
+		errs = append(errs, errors.New("bikers_on_bike must be positive"))
+	}
+	if c.RoundIterations <= 0 {
+		errs = append(errs, errors.New("round_iterations must be positive"))
+	}
+	if c.BikerAgentCount < 0 || c.LootBoxCount < 0 {
+		errs = append(errs, errors.New("agent and lootbox counts cannot be negative"))
+	}
+	for team, count := range c.TeamCounts {
+		if count < 0 {
+			errs = append(errs, fmt.Errorf("team_counts: %s cannot have a negative number of agents", team))
+		}
+	}
+	if c.MegaBikeCount <= 0 {
+		errs = append(errs, errors.New("mega_bike_count must be positive"))
+	} else if c.MegaBikeCount*c.BikersOnBike < c.AgentCount() {
+		errs = append(errs, fmt.Errorf("%d mega bikes cannot seat %d agents", c.MegaBikeCount, c.AgentCount()))
+	}
+	if c.MassBike <= 0 || c.MassBiker < 0 || c.MassAudi <= 0 || c.MassLootBox <= 0 {
+		errs = append(errs, errors.New("bike, audi and lootbox masses must be positive"))
+	}
+	if c.BikerMaxForce < 0 || c.AudiMaxForce < 0 || c.DragCoefficient < 0 {
+		errs = append(errs, errors.New("forces and drag coefficient cannot be negative"))
+	}
+	if c.Drag < QuadraticDrag || c.Drag > LinearDrag {
+		errs = append(errs, fmt.Errorf("invalid drag %d", int(c.Drag)))
+	}
+	if c.MaxVelocity <= 0 || c.TimeStep <= 0 {
+		errs = append(errs, errors.New("max_velocity and time_step must be positive"))
+	}
+	if c.SubSteps <= 0 {
+		errs = append(errs, errors.New("sub_steps must be positive"))
+	}
+	if c.MaxTurnRate <= 0 || c.MaxTurnRate > 1 {
+		errs = append(errs, errors.New("max_turn_rate must be between 0 (excluded) and 1"))
+	}
+	if c.TurnInertia < 0 || c.TurnMomentumLoss < 0 || c.TurnMomentumLoss > 1 {
+		errs = append(errs, errors.New("turn_inertia cannot be negative and turn_momentum_loss must be between 0 and 1"))
+	}
+	if c.MovingDepletion < 0 || c.DeliberativeDemocracyPenalty < 0 || c.LeadershipDemocracyPenalty < 0 || c.CouncilDemocracyPenalty < 0 {
+		errs = append(errs, errors.New("energy depletion and governance penalties cannot be negative"))
+	}
+	if c.PedalExponent <= 0 {
+		errs = append(errs, errors.New("pedal_exponent must be positive"))
+	}
+	if c.LoadCost < 0 || c.BrakingCost < 0 || c.SteeringCost < 0 || c.BasalMetabolism < 0 {
+		errs = append(errs, errors.New("energy costs cannot be negative"))
+	}
+	if c.LimboEnergyPenalty > 0 {
+		errs = append(errs, errors.New("limbo_energy_penalty is added to the energy level and cannot be positive"))
+	}
+	if c.LootSharing < EqualSplit || c.LootSharing > Bargaining {
+		errs = append(errs, fmt.Errorf("invalid loot_sharing %d", int(c.LootSharing)))
+	}
+	if c.LootBoxPlacement < UniformPlacement || c.LootBoxPlacement > HotspotPlacement {
+		errs = append(errs, fmt.Errorf("invalid loot_box_placement %d", int(c.LootBoxPlacement)))
+	}
+	if c.LootBoxPlacement == HotspotPlacement && c.LootBoxHotspots <= 0 {
+		errs = append(errs, errors.New("loot_box_hotspots must be positive when placing lootboxes around hotspots"))
+	}
+	if c.LootBoxColouring < RandomColours || c.LootBoxColouring > ZonedColours {
+		errs = append(errs, fmt.Errorf("invalid loot_box_colouring %d", int(c.LootBoxColouring)))
+	}
+	if c.LootBoxResources < UniformResources || c.LootBoxResources > ExponentialResources {
+		errs = append(errs, fmt.Errorf("invalid loot_box_resources %d", int(c.LootBoxResources)))
+	}
+	if c.LootBoxMinResources < 0 || c.LootBoxMaxResources < c.LootBoxMinResources {
+		errs = append(errs, errors.New("loot_box_min_resources cannot be negative or above loot_box_max_resources"))
+	}
+	if c.LootBoxHotspotSpread < 0 || c.LootBoxRichRegions < 0 || c.LootBoxRichRadius < 0 || c.LootBoxRichMultiplier < 0 {
+		errs = append(errs, errors.New("lootbox hotspot spread and rich regions cannot be negative"))
+	}
+	if c.LootBoxScarcityPeriod < 0 || c.LootBoxScarcityRounds < 0 || c.LootBoxScarcityRounds > c.LootBoxScarcityPeriod {
+		errs = append(errs, errors.New("loot_box_scarcity_rounds must be between 0 and loot_box_scarcity_period"))
+	}
+	if c.LootBoxDecay < 0 || c.LootBoxDecay > 1 {
+		errs = append(errs, errors.New("loot_box_decay must be between 0 and 1"))
+	}
+	if c.LootBoxLifetime < 0 || c.LootBoxDriftForce < 0 {
+		errs = append(errs, errors.New("loot_box_lifetime and loot_box_drift_force cannot be negative"))
+	}
+	for _, mode := range []ObservationMode{c.LootBoxResourceObservation, c.LootBoxColourObservation} {
+		if mode < ExactObservation || mode > NoisyObservation {
+			errs = append(errs, fmt.Errorf("invalid lootbox observation mode %d", int(mode)))
+		}
+	}
+	if c.LootBoxRevealRadius < 0 || c.LootBoxResourceNoise < 0 {
+		errs = append(errs, errors.New("loot_box_reveal_radius and loot_box_resource_noise cannot be negative"))
+	}
+	if c.LootBoxColourNoise < 0 || c.LootBoxColourNoise > 1 {
+		errs = append(errs, errors.New("loot_box_colour_noise must be between 0 and 1"))
+	}
+	if c.AudiCount <= 0 {
+		errs = append(errs, errors.New("audi_count must be positive"))
+	}
+	if len(c.AudiStrategies) > c.AudiCount {
+		errs = append(errs, fmt.Errorf("audi_strategies lists %d strategies for %d audis", len(c.AudiStrategies), c.AudiCount))
+	}
+	for _, strategy := range append([]AudiTargetingStrategy{c.AudiStrategy}, c.AudiStrategies...) {
+		if strategy < TargetSlowest || strategy > TargetRoundRobin {
+			errs = append(errs, fmt.Errorf("invalid audi strategy %d", int(strategy)))
+		}
+	}
+	if c.AudiPursuit < DirectPursuit || c.AudiPursuit > InterceptPursuit {
+		errs = append(errs, fmt.Errorf("invalid audi_pursuit %d", int(c.AudiPursuit)))
+	}
+	if c.AudiMaxTurnRate <= 0 || c.AudiMaxTurnRate > 1 {
+		errs = append(errs, errors.New("audi_max_turn_rate must be between 0 (excluded) and 1"))
+	}
+	if c.AudiCollision < KillAllRiders || c.AudiCollision > DamageRiders {
+		errs = append(errs, fmt.Errorf("invalid audi_collision %d", int(c.AudiCollision)))
+	}
+	if c.AudiKillProbability < 0 || c.AudiKillProbability > 1 {
+		errs = append(errs, errors.New("audi_kill_probability must be between 0 and 1"))
+	}
+	if c.AudiDamage < 0 || c.AudiShieldCost < 0 || c.AudiCooldown < 0 {
+		errs = append(errs, errors.New("audi_damage, audi_shield_cost and audi_cooldown cannot be negative"))
+	}
+	if c.VoteAction < PLURALITY || c.VoteAction > COPELANDSCORING {
+		errs = append(errs, fmt.Errorf("invalid vote_action %d", int(c.VoteAction)))
+	}
+	if c.ReferendumPeriod < 0 {
+		errs = append(errs, errors.New("referendum_period cannot be negative"))
+	}
+	if c.ReferendumPetition < 0 || c.ReferendumPetition > 1 {
+		errs = append(errs, errors.New("referendum_petition must be between 0 and 1"))
+	}
+	if c.ReferendumSupermajority <= 0 || c.ReferendumSupermajority > 1 {
+		errs = append(errs, errors.New("referendum_supermajority must be between 0 (excluded) and 1"))
+	}
+	if c.KickoutThreshold < 0 || c.KickoutThreshold >= 1 || c.AdmissionQuorum < 0 || c.AdmissionQuorum >= 1 {
+		errs = append(errs, errors.New("kickout_threshold and admission_quorum must be between 0 and 1 (excluded)"))
+	}
+	if c.LootAllocation < MeanAllocation || c.LootAllocation > EqualAllocation {
+		errs = append(errs, fmt.Errorf("invalid loot_allocation %d", int(c.LootAllocation)))
+	}
+	if c.RulerTerm < 0 || c.TermLimit < 0 {
+		errs = append(errs, errors.New("ruler_term and term_limit cannot be negative"))
+	}
+	if c.AmendmentSupermajority <= 0 || c.AmendmentSupermajority > 1 || c.ImpeachmentSupermajority <= 0 || c.ImpeachmentSupermajority > 1 {
+		errs = append(errs, errors.New("amendment_supermajority and impeachment_supermajority must be between 0 (excluded) and 1"))
+	}
+	if c.CouncilSize < 1 {
+		errs = append(errs, errors.New("council_size must be at least 1"))
+	}
+	for _, sanction := range c.SanctionLadder {
+		if sanction < WarningSanction || sanction > KickoutSanction {
+			errs = append(errs, fmt.Errorf("invalid sanction %d in sanction_ladder", int(sanction)))
+		}
+	}
+	if c.SanctionFine < 0 || c.SanctionDuration < 1 || c.SanctionAmnesty < 0 {
+		errs = append(errs, errors.New("sanction_fine and sanction_amnesty cannot be negative and sanction_duration must be at least 1"))
+	}
+	if c.SanctionVoteWeight < 0 || c.SanctionVoteWeight > 1 {
+		errs = append(errs, errors.New("sanction_vote_weight must be between 0 and 1"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,360 @@
+package utils_test
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, name string, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultSimConfigIsValid(t *testing.T) {
+	assert.NoError(t, utils.DefaultSimConfig().Validate())
+}
+
+func TestLoadSimConfigYAML(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+grid_width: 100
+limbo_energy_penalty: -0.5
+vote_action: borda_count
+`)
+	config, err := utils.LoadSimConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, config.GridWidth)
+	assert.Equal(t, -0.5, config.LimboEnergyPenalty)
+	assert.Equal(t, utils.BORDACOUNT, config.VoteAction)
+	// parameters missing from the file keep their defaults
+	assert.Equal(t, utils.GridHeight, config.GridHeight)
+	assert.Equal(t, utils.BikerAgentCount, config.BikerAgentCount)
+}
+
+func TestLoadSimConfigJSON(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"round_iterations": 10, "vote_action": "approval"}`)
+	config, err := utils.LoadSimConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, config.RoundIterations)
+	assert.Equal(t, utils.APPROVAL, config.VoteAction)
+}
+
+func TestLoadSimConfigRejectsInvalidFiles(t *testing.T) {
+	unknownField := writeConfigFile(t, "unknown.yaml", "grid_wdth: 10\n")
+	_, err := utils.LoadSimConfig(unknownField)
+	assert.Error(t, err, "misspelt parameters should be reported")
+
+	unknownMethod := writeConfigFile(t, "method.json", `{"vote_action": "dice"}`)
+	_, err = utils.LoadSimConfig(unknownMethod)
+	assert.Error(t, err, "unknown voting methods should be reported")
+
+	tooFewBikes := writeConfigFile(t, "bikes.yml", "biker_agent_count: 100\nmega_bike_count: 2\n")
+	_, err = utils.LoadSimConfig(tooFewBikes)
+	assert.Error(t, err, "configurations that cannot seat every agent should be rejected")
+
+	_, err = utils.LoadSimConfig(writeConfigFile(t, "config.toml", ""))
+	assert.Error(t, err, "unsupported formats should be reported")
+}
+
+func TestLoadSimConfigTeamCounts(t *testing.T) {
+	path := writeConfigFile(t, "teams.yaml", `
+team_counts:
+  team1: 10
+  team5: 2
+  base: 0
+`)
+	config, err := utils.LoadSimConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"team1": 10, "team5": 2, "base": 0}, config.TeamCounts)
+	assert.Equal(t, 12, config.AgentCount(), "team counts take precedence over biker_agent_count")
+
+	negative := writeConfigFile(t, "negative.yaml", "team_counts: {team1: -1}\n")
+	_, err = utils.LoadSimConfig(negative)
+	assert.Error(t, err, "negative team counts should be rejected")
+}
+
+func TestSimConfigWithParameters(t *testing.T) {
+	base := utils.DefaultSimConfig()
+	base.TeamCounts = map[string]int{"team1": 4, "team2": 4}
+
+	config, err := base.WithParameters(map[string]any{
+		"limbo_energy_penalty": -0.5,
+		"vote_action":          "borda_count",
+		"team_counts":          map[string]any{"team5": 3},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, -0.5, config.LimboEnergyPenalty)
+	assert.Equal(t, utils.BORDACOUNT, config.VoteAction)
+	assert.Equal(t, map[string]int{"team5": 3}, config.TeamCounts, "team counts should be replaced rather than merged")
+	assert.Equal(t, map[string]int{"team1": 4, "team2": 4}, base.TeamCounts, "the base configuration should not be modified")
+
+	_, err = base.WithParameters(map[string]any{"grid_wdth": 10})
+	assert.Error(t, err, "misspelt parameters should be reported")
+	_, err = base.WithParameters(map[string]any{"mega_bike_count": 0})
+	assert.Error(t, err, "invalid configurations should be reported")
+}
+
+func TestParseSeeds(t *testing.T) {
+	seeds, err := utils.ParseSeeds("3, 1-3,10")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1, 2, 3, 10}, seeds)
+
+	seeds, err = utils.ParseSeeds("-5, -3--2, -1--1, +4")
+	assert.NoError(t, err, "negative seeds should be read before the range separator")
+	assert.Equal(t, []int64{-5, -3, -2, -1, 4}, seeds)
+
+	for _, list := range []string{"", "a", "4-2", "1-", "-", "--5", "-2--3", "1-1000000000", "-9223372036854775808-9223372036854775807", "-1-1"} {
+		_, err := utils.ParseSeeds(list)
+		assert.Error(t, err, "%q should be rejected", list)
+	}
+	seeds, err = utils.ParseSeeds("1-10000")
+	assert.NoError(t, err, "a range should be able to list as many seeds as the limit")
+	assert.Len(t, seeds, 10000)
+}
+
+func TestLoadSimConfigBoundary(t *testing.T) {
+	assert.Equal(t, utils.Unbounded, utils.DefaultSimConfig().Boundary, "the rules state there is no physical boundary")
+
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "torus.yaml", "boundary: torus\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.Torus, config.Boundary)
+
+	_, err = utils.LoadSimConfig(writeConfigFile(t, "walls.json", `{"boundary": "walls"}`))
+	assert.Error(t, err, "unknown boundary modes should be reported")
+}
+
+func TestLoadSimConfigPhysicsModel(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "physics.yaml", "drag: linear\nmax_velocity: 3\ntime_step: 0.5\nsub_steps: 4\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.LinearDrag, config.Drag)
+	assert.Equal(t, 3.0, config.MaxVelocity)
+	assert.Equal(t, 0.5, config.TimeStep)
+	assert.Equal(t, 4, config.SubSteps)
+
+	for _, contents := range []string{"drag: cubic\n", "max_velocity: 0\n", "time_step: -1\n", "sub_steps: 0\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "physics.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigTurning(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "turning.yaml", "max_turn_rate: 0.25\nturn_inertia: 0\nturn_momentum_loss: 0\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.25, config.MaxTurnRate)
+	assert.Equal(t, 0.0, config.TurnInertia)
+	assert.Equal(t, 0.0, config.TurnMomentumLoss)
+
+	for _, contents := range []string{"max_turn_rate: 0\n", "max_turn_rate: 1.5\n", "turn_inertia: -1\n", "turn_momentum_loss: 2\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "turning.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigEnergyCosts(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "energy.yaml", "pedal_exponent: 2\nload_cost: 0.01\nbasal_metabolism: 0.02\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, config.PedalExponent)
+	assert.Equal(t, 0.01, config.LoadCost)
+	assert.Equal(t, 0.02, config.BasalMetabolism)
+
+	for _, contents := range []string{"pedal_exponent: 0\n", "braking_cost: -1\n", "basal_metabolism: -0.1\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "energy.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigAudiStrategy(t *testing.T) {
+	assert.Equal(t, utils.TargetSlowest, utils.DefaultSimConfig().AudiStrategy)
+
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "audi.json", `{"audi_strategy": "round_robin"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.TargetRoundRobin, config.AudiStrategy)
+
+	_, err = utils.LoadSimConfig(writeConfigFile(t, "audi.yaml", "audi_strategy: fastest\n"))
+	assert.Error(t, err, "unknown audi strategies should be reported")
+}
+
+func TestLoadSimConfigAudiPursuit(t *testing.T) {
+	assert.Equal(t, utils.DirectPursuit, utils.DefaultSimConfig().AudiPursuit)
+
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "pursuit.yaml", "audi_pursuit: intercept\naudi_max_turn_rate: 0.25\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.InterceptPursuit, config.AudiPursuit)
+	assert.Equal(t, 0.25, config.AudiMaxTurnRate)
+
+	for _, contents := range []string{"audi_pursuit: ahead\n", "audi_max_turn_rate: 0\n", "audi_max_turn_rate: 2\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "pursuit.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigAudis(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "audis.yaml", "audi_count: 3\naudi_strategy: nearest\naudi_strategies: [random, richest]\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, config.AudiCount)
+	assert.Equal(t, utils.TargetRandom, config.AudiStrategyOf(0))
+	assert.Equal(t, utils.TargetRichest, config.AudiStrategyOf(1))
+	assert.Equal(t, utils.TargetNearest, config.AudiStrategyOf(2), "unlisted audis should use audi_strategy")
+
+	for _, contents := range []string{"audi_count: 0\n", "audi_strategies: [nearest, nearest]\n", "audi_count: 2\naudi_strategies: [fastest]\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "audis.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+
+	swept, err := config.WithParameters(map[string]any{"audi_strategies": []string{"slowest"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []utils.AudiTargetingStrategy{utils.TargetSlowest}, swept.AudiStrategies)
+}
+
+func TestLoadSimConfigAudiCollision(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "collision.yaml", "audi_collision: damage\naudi_damage: 2\naudi_shield_cost: 1.5\naudi_cooldown: 3\naudi_respawns: true\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.DamageRiders, config.AudiCollision)
+	assert.Equal(t, 2.0, config.AudiDamage)
+	assert.Equal(t, 1.5, config.AudiShieldCost)
+	assert.Equal(t, 3, config.AudiCooldown)
+	assert.True(t, config.AudiRespawns)
+	assert.Equal(t, utils.KillAllRiders, utils.DefaultSimConfig().AudiCollision)
+
+	for _, contents := range []string{"audi_collision: explode\n", "audi_kill_probability: 1.5\n", "audi_cooldown: -1\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "collision.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigLootBoxSpawning(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "lootboxes.yaml", "loot_box_placement: hotspots\nloot_box_colouring: zoned\nloot_box_resources: exponential\nloot_box_scarcity_period: 10\nloot_box_scarcity_rounds: 4\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.HotspotPlacement, config.LootBoxPlacement)
+	assert.Equal(t, utils.ZonedColours, config.LootBoxColouring)
+	assert.Equal(t, utils.ExponentialResources, config.LootBoxResources)
+	assert.Equal(t, 10, config.LootBoxScarcityPeriod)
+	assert.Equal(t, 4, config.LootBoxScarcityRounds)
+
+	for _, contents := range []string{
+		"loot_box_placement: everywhere\n",
+		"loot_box_placement: hotspots\nloot_box_hotspots: 0\n",
+		"loot_box_min_resources: 9\n",
+		"loot_box_scarcity_period: 3\nloot_box_scarcity_rounds: 4\n",
+	} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "lootboxes.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigLootBoxLifecycle(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "lifecycle.yaml", "loot_box_decay: 0.1\nloot_box_lifetime: 20\nloot_box_drift_force: 0.5\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, config.LootBoxDecay)
+	assert.Equal(t, 20, config.LootBoxLifetime)
+	assert.Equal(t, 0.5, config.LootBoxDriftForce)
+
+	for _, contents := range []string{"loot_box_decay: 1.5\n", "loot_box_lifetime: -1\n", "mass_loot_box: 0\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "lifecycle.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigLootSharing(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "sharing.yaml", "loot_sharing: bargaining\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.Bargaining, config.LootSharing)
+	assert.Equal(t, "bargaining", config.LootSharing.String())
+
+	_, err = utils.LoadSimConfig(writeConfigFile(t, "sharing.yaml", "loot_sharing: first_come\n"))
+	assert.Error(t, err)
+}
+
+func TestLoadSimConfigLootBoxObservation(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "observation.yaml", "loot_box_resource_observation: noisy\nloot_box_colour_observation: hidden\nloot_box_reveal_radius: 20\nloot_box_resource_noise: 0.5\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.NoisyObservation, config.LootBoxResourceObservation)
+	assert.Equal(t, utils.HiddenObservation, config.LootBoxColourObservation)
+	assert.Equal(t, 20.0, config.LootBoxRevealRadius)
+	assert.Equal(t, 0.5, config.LootBoxResourceNoise)
+	assert.Equal(t, "unknown", utils.HiddenColour.String())
+
+	for _, contents := range []string{"loot_box_colour_observation: blurry\n", "loot_box_reveal_radius: -1\n", "loot_box_colour_noise: 2\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "observation.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigReferendums(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "referendums.yaml", "referendum_period: 10\nreferendum_petition: 0.5\nreferendum_supermajority: 0.75\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, config.ReferendumPeriod)
+	assert.Equal(t, 0.5, config.ReferendumPetition)
+	assert.Equal(t, 0.75, config.ReferendumSupermajority)
+
+	for _, contents := range []string{"referendum_period: -1\n", "referendum_petition: 1.5\n", "referendum_supermajority: 0\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "referendums.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigConstitution(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "constitution.yaml", "kickout_threshold: 0.75\nadmission_quorum: 0.25\nloot_allocation: median\nruler_term: 8\namendment_supermajority: 0.8\nterm_limit: 2\nimpeachment_supermajority: 0.5\ncouncil_size: 5\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.75, config.KickoutThreshold)
+	assert.Equal(t, 0.25, config.AdmissionQuorum)
+	assert.Equal(t, utils.MedianAllocation, config.LootAllocation)
+	assert.Equal(t, 8, config.RulerTerm)
+	assert.Equal(t, 0.8, config.AmendmentSupermajority)
+	assert.Equal(t, 2, config.TermLimit)
+	assert.Equal(t, 0.5, config.ImpeachmentSupermajority)
+	assert.Equal(t, 5, config.CouncilSize)
+
+	for _, contents := range []string{"kickout_threshold: 1\n", "loot_allocation: fair\n", "ruler_term: -2\n", "amendment_supermajority: 0\n", "term_limit: -1\n", "impeachment_supermajority: 1.5\n", "council_size: 0\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "constitution.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestLoadSimConfigSanctions(t *testing.T) {
+	config, err := utils.LoadSimConfig(writeConfigFile(t, "sanctions.yaml", "sanction_ladder: [fine, kickout]\nsanction_fine: 0.2\nsanction_duration: 5\nsanction_vote_weight: 0.25\nsanction_amnesty: 10\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []utils.SanctionType{utils.FineSanction, utils.KickoutSanction}, config.SanctionLadder)
+	assert.Equal(t, 0.2, config.SanctionFine)
+	assert.Equal(t, 5, config.SanctionDuration)
+	assert.Equal(t, 0.25, config.SanctionVoteWeight)
+	assert.Equal(t, 10, config.SanctionAmnesty)
+
+	for _, contents := range []string{"sanction_ladder: [jail]\n", "sanction_fine: -0.1\n", "sanction_duration: 0\n", "sanction_vote_weight: 2\n", "sanction_amnesty: -1\n"} {
+		_, err = utils.LoadSimConfig(writeConfigFile(t, "sanctions.yaml", contents))
+		assert.Error(t, err, "%q should be rejected", contents)
+	}
+}
+
+func TestSanctionOf(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	for offence, sanction := range []utils.SanctionType{utils.WarningSanction, utils.FineSanction, utils.VoteWeightSanction, utils.LootExclusionSanction, utils.KickoutSanction, utils.KickoutSanction} {
+		assert.Equal(t, sanction, config.SanctionOf(offence+1), "offence %d", offence+1)
+	}
+
+	config.SanctionLadder = []utils.SanctionType{utils.FineSanction, utils.LootExclusionSanction}
+	assert.Equal(t, utils.FineSanction, config.SanctionOf(1))
+	assert.Equal(t, utils.LootExclusionSanction, config.SanctionOf(2))
+	assert.Equal(t, utils.LootExclusionSanction, config.SanctionOf(3), "later offences should get the last sanction of the ladder")
+}
+
+func TestEnumNames(t *testing.T) {
+	text, err := utils.BORDACOUNT.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "borda_count", string(text))
+	var method utils.VoteMethod
+	assert.NoError(t, method.UnmarshalText(text))
+	assert.Equal(t, utils.BORDACOUNT, method)
+
+	assert.Equal(t, "unknown", utils.SanctionType(10).String())
+	_, err = utils.SanctionType(-1).MarshalText()
+	assert.EqualError(t, err, "invalid sanction -1")
+	var sanction utils.SanctionType
+	assert.EqualError(t, sanction.UnmarshalText([]byte("jail")), `unknown sanction "jail"`)
+}
@@ -3,6 +3,7 @@ package voting
 import (
 	"SOMAS2023/internal/common/utils"
 	"errors"
+	"slices"
 	"sort"
 
 	"github.com/google/uuid"
@@ -31,14 +32,16 @@ func (ivm IdVoteMap) GetVotes() map[uuid.UUID]float64 {
 }
 
 // this function will take in a list of maps from ids to their corresponding vote (yes/ no in the case of acceptance)
-// and retunr a list of ids that can be accepted according to some metric (ie more than half voted yes)
+// and retunr a list of ids that can be accepted according to some metric (ie more than the quorum share of the voters voted yes)
 // ranked according to a metric (ie overall number of yes's)
-func GetAcceptanceRanking(rankings map[uuid.UUID]map[uuid.UUID]bool, weights map[uuid.UUID]float64) []uuid.UUID {
+func GetAcceptanceRanking(rankings map[uuid.UUID]map[uuid.UUID]bool, weights map[uuid.UUID]float64, quorumShare float64) []uuid.UUID {
 	// sum the number of acceptance rankings for all the agents
 	cumulativeRank := make(map[uuid.UUID]float64)
-	quorum := float64(len(rankings)) / 2.0
-	for voter, ranking := range rankings {
-		for agent, outcome := range ranking {
+	quorum := float64(len(rankings)) * quorumShare
+	for _, voter := range utils.SortedIDs(rankings) {
+		ranking := rankings[voter]
+		for _, agent := range utils.SortedIDs(ranking) {
+			outcome := ranking[agent]
 			val, ok := cumulativeRank[agent]
 			if outcome && ok {
 				cumulativeRank[agent] = val + weights[voter]
@@ -54,14 +57,9 @@ func GetAcceptanceRanking(rankings map[uuid.UUID]map[uuid.UUID]bool, weights map
 		}
 	}
 
-	// sort according to ranking
-	unsortedAcceptedList := make([]uuid.UUID, len(passedUnsorted))
-	i := 0
-	for key := range passedUnsorted {
-		unsortedAcceptedList[i] = key
-		i += 1
-	}
-	sort.Slice(unsortedAcceptedList, func(i, j int) bool {
+	// sort according to ranking (ties keep the order of the IDs)
+	unsortedAcceptedList := utils.SortedIDs(passedUnsorted)
+	sort.SliceStable(unsortedAcceptedList, func(i, j int) bool {
 		return passedUnsorted[unsortedAcceptedList[i]] > passedUnsorted[unsortedAcceptedList[j]]
 	})
 	return unsortedAcceptedList
@@ -70,8 +68,9 @@ func GetAcceptanceRanking(rankings map[uuid.UUID]map[uuid.UUID]bool, weights map
 
 func SumOfValues(voteMap IVoter) float64 {
 	sum := 0.0
-	for _, value := range voteMap.GetVotes() {
-		sum += value
+	votes := voteMap.GetVotes()
+	for _, id := range utils.SortedIDs(votes) {
+		sum += votes[id]
 	}
 	return sum
 }
@@ -90,18 +89,19 @@ func CumulativeDist(voters map[uuid.UUID]IVoter, weights map[uuid.UUID]float64)
 		aggregateVotes[voter] = 0.0
 	}
 
-	for agentID, voter := range voters {
+	for _, agentID := range utils.SortedIDs(voters) {
+		voter := voters[agentID]
 		voteSum := SumOfValues(voter)
 		votes := voter.GetVotes()
 		weight := weights[agentID]
-		for id, vote := range votes {
-			aggregateVotes[id] += weight * vote / voteSum
+		for _, id := range utils.SortedIDs(votes) {
+			aggregateVotes[id] += weight * votes[id] / voteSum
 		}
 	}
 
 	normalizeFactor := 0.0
-	for _, vote := range aggregateVotes {
-		normalizeFactor += vote
+	for _, id := range utils.SortedIDs(aggregateVotes) {
+		normalizeFactor += aggregateVotes[id]
 	}
 	if normalizeFactor == 0.0 {
 		panic("all votes summed to zero")
@@ -113,6 +113,78 @@ func CumulativeDist(voters map[uuid.UUID]IVoter, weights map[uuid.UUID]float64)
 	return aggregateVotes
 }
 
+// AllocationFromDist combines the allocation votes of the voters according to the allocation method, the result
+// sums to 1 (assumes all the maps contain a voting between 0-1 for each option)
+func AllocationFromDist(voters map[uuid.UUID]IVoter, weights map[uuid.UUID]float64, method utils.AllocationMethod) map[uuid.UUID]float64 {
+	switch method {
+	case utils.MedianAllocation:
+		return MedianDist(voters, weights)
+	case utils.EqualAllocation:
+		if len(voters) == 0 {
+			panic("no votes provided")
+		}
+		allocation := make(map[uuid.UUID]float64, len(voters))
+		for voter := range voters {
+			allocation[voter] = 1.0 / float64(len(voters))
+		}
+		return allocation
+	default:
+		return CumulativeDist(voters, weights)
+	}
+}
+
+// MedianDist gives every option the weighted median of the (normalised) votes it got, normalised to sum to 1.
+// Unlike the mean, a single voter cannot drag the share of an option far from what most voters want. If every
+// median is zero it falls back to CumulativeDist.
+func MedianDist(voters map[uuid.UUID]IVoter, weights map[uuid.UUID]float64) map[uuid.UUID]float64 {
+	if len(voters) == 0 {
+		panic("no votes provided")
+	}
+	options := make(map[uuid.UUID]bool)
+	for voter, voteMap := range voters {
+		options[voter] = true
+		for id := range voteMap.GetVotes() {
+			options[id] = true
+		}
+	}
+
+	medians := make(map[uuid.UUID]float64, len(options))
+	total := 0.0
+	for _, option := range utils.SortedIDs(options) {
+		votes := make([]float64, 0, len(voters))
+		voteWeights := make(map[float64]float64)
+		totalWeight := 0.0
+		for _, voter := range utils.SortedIDs(voters) {
+			vote := 0.0
+			if voteSum := SumOfValues(voters[voter]); voteSum > 0 {
+				vote = voters[voter].GetVotes()[option] / voteSum
+			}
+			if _, ok := voteWeights[vote]; !ok {
+				votes = append(votes, vote)
+			}
+			voteWeights[vote] += weights[voter]
+			totalWeight += weights[voter]
+		}
+		sort.Float64s(votes)
+		cumulativeWeight := 0.0
+		for _, vote := range votes {
+			cumulativeWeight += voteWeights[vote]
+			if cumulativeWeight >= totalWeight/2 {
+				medians[option] = vote
+				break
+			}
+		}
+		total += medians[option]
+	}
+	if total == 0.0 {
+		return CumulativeDist(voters, weights)
+	}
+	for option, median := range medians {
+		medians[option] = median / total
+	}
+	return medians
+}
+
 // return the votesMap
 func GetVotesMap(voters map[uuid.UUID]IVoter) map[uuid.UUID]map[uuid.UUID]float64 {
 	if len(voters) == 0 {
@@ -137,10 +209,10 @@ func GetVotesMap(voters map[uuid.UUID]IVoter) map[uuid.UUID]map[uuid.UUID]float6
 
 // returns the winner accoring to chosen voting strategy (assumes all the maps contain a voting between 0-1
 // for each option, and that all the votings sum to 1)
-func WinnerFromDist(voters map[uuid.UUID]IVoter, voteWeight map[uuid.UUID]float64) uuid.UUID {
+func WinnerFromDist(voters map[uuid.UUID]IVoter, voteWeight map[uuid.UUID]float64, method utils.VoteMethod) uuid.UUID {
 	VotesOfAgents := GetVotesMap(voters)
 	var winner uuid.UUID
-	switch utils.VoteAction {
+	switch method {
 	case utils.PLURALITY:
 		winner = Plurality(VotesOfAgents, voteWeight)
 	case utils.RUNOFF:
@@ -158,6 +230,24 @@ func WinnerFromDist(voters map[uuid.UUID]IVoter, voteWeight map[uuid.UUID]float6
 	return winner
 }
 
+// CouncilFromDist elects up to seats of the candidates, the ones with the most weighted votes (ties keep the
+// order of the candidates). Votes for agents that aren't candidates are discarded.
+func CouncilFromDist(voters map[uuid.UUID]IVoter, weights map[uuid.UUID]float64, candidates []uuid.UUID, seats int) []uuid.UUID {
+	totals := make(map[uuid.UUID]float64, len(candidates))
+	for _, voter := range utils.SortedIDs(voters) {
+		votes := voters[voter].GetVotes()
+		for _, candidate := range candidates {
+			totals[candidate] += weights[voter] * votes[candidate]
+		}
+	}
+
+	council := slices.Clone(candidates)
+	sort.SliceStable(council, func(i, j int) bool {
+		return totals[council[i]] > totals[council[j]]
+	})
+	return council[:min(seats, len(council))]
+}
+
 func WinnerFromGovernance(voters []GovernanceVote) (utils.Governance, error) {
 	// check if length of votes is greater than one
 	if len(voters) == 0 {
@@ -181,13 +271,13 @@ func WinnerFromGovernance(voters []GovernanceVote) (utils.Governance, error) {
 
 	// Summing up the votes for each governance type
 	for _, vote := range voters {
-		for governance, votes := range vote {
-			voteTotals[governance] += votes
+		for governance := utils.Democracy; governance < utils.Invalid; governance++ {
+			voteTotals[governance] += vote[governance]
 		}
 	}
 	// Finding the governance type with the highest votes
-	for governance, votes := range voteTotals {
-		if votes > highestVotes {
+	for governance := utils.Democracy; governance < utils.Invalid; governance++ {
+		if votes := voteTotals[governance]; votes > highestVotes {
 			highestVotes = votes
 			winner = governance
 		}
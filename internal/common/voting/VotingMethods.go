@@ -5,6 +5,8 @@ import (
 	"sort"
 
 	"github.com/google/uuid"
+
+	"SOMAS2023/internal/common/utils"
 )
 
 // Auxiliary Structure for Sorting
@@ -21,10 +23,12 @@ func Plurality(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.
 
 	//initialise the votes with weights
 	var voteList []map[uuid.UUID]float64
-	for agent, votes := range voteMap {
+	for _, agent := range utils.SortedIDs(voteMap) {
+		votes := voteMap[agent]
 		weight := voteWeight[agent]
 		weightedvotes := make(map[uuid.UUID]float64)
-		for key, value := range votes {
+		for _, key := range utils.SortedIDs(votes) {
+			value := votes[key]
 			weightedvotes[key] = value * weight
 		}
 		voteList = append(voteList, weightedvotes)
@@ -37,7 +41,8 @@ func Plurality(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.
 	for _, preference := range voteList {
 		var maxPreference float64
 		var firstLootBoxChoice uuid.UUID
-		for lootBox, value := range preference {
+		for _, lootBox := range utils.SortedIDs(preference) {
+			value := preference[lootBox]
 			if value > maxPreference {
 				firstLootBoxChoice = lootBox
 				maxPreference = value
@@ -49,7 +54,8 @@ func Plurality(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.
 	// final step: we need to find the winner with highest count number in map.
 	var maxVotes float64
 
-	for lootBox, votes := range voteCount {
+	for _, lootBox := range utils.SortedIDs(voteCount) {
+		votes := voteCount[lootBox]
 		if votes > maxVotes {
 			maxVotes = votes
 			winner = lootBox
@@ -69,10 +75,12 @@ func Runoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.UUI
 	*/
 	//initialise the votes with weights
 	var voteList []map[uuid.UUID]float64
-	for agent, votes := range voteMap {
+	for _, agent := range utils.SortedIDs(voteMap) {
+		votes := voteMap[agent]
 		weight := voteWeight[agent]
 		weightedvotes := make(map[uuid.UUID]float64)
-		for key, value := range votes {
+		for _, key := range utils.SortedIDs(votes) {
+			value := votes[key]
 			weightedvotes[key] = value * weight
 		}
 		voteList = append(voteList, weightedvotes)
@@ -87,7 +95,8 @@ func Runoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.UUI
 	for _, preference := range voteList {
 		var maxPreference float64
 		var firstLootBoxChoice uuid.UUID
-		for lootBox, value := range preference {
+		for _, lootBox := range utils.SortedIDs(preference) {
+			value := preference[lootBox]
 			if value > maxPreference {
 				firstLootBoxChoice = lootBox
 				maxPreference = value
@@ -99,7 +108,8 @@ func Runoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.UUI
 	// find the two candidates with most first-placed votes
 	var maxVotes1, maxVotes2 float64
 	var winner1, winner2 uuid.UUID
-	for lootBox, votes := range voteCount {
+	for _, lootBox := range utils.SortedIDs(voteCount) {
+		votes := voteCount[lootBox]
 		if votes > maxVotes1 {
 			winner2 = winner1
 			maxVotes2 = maxVotes1
@@ -143,10 +153,12 @@ func BordaCount(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid
 	*/
 	//initialise the votes with weights
 	voteListMap := make(map[uuid.UUID]map[uuid.UUID]float64)
-	for agent, votes := range voteMap {
+	for _, agent := range utils.SortedIDs(voteMap) {
+		votes := voteMap[agent]
 		weight := voteWeight[agent]
 		weightedvotes := make(map[uuid.UUID]float64)
-		for key, value := range votes {
+		for _, key := range utils.SortedIDs(votes) {
+			value := votes[key]
 			weightedvotes[key] = value * weight
 		}
 		voteListMap[agent] = weightedvotes
@@ -158,23 +170,25 @@ func BordaCount(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid
 
 	// initialise the map with all candidates
 	for _, preference := range voteListMap {
-		for key := range preference {
+		for _, key := range utils.SortedIDs(preference) {
 			voteCount[key] = 0
 		}
 	}
 
 	// covert the unodered map into ordered list
 	ss := make(map[uuid.UUID][]kv)
-	for agent, preference := range voteListMap {
+	for _, agent := range utils.SortedIDs(voteListMap) {
+		preference := voteListMap[agent]
 		var s []kv
-		for k, v := range preference {
+		for _, k := range utils.SortedIDs(preference) {
+			v := preference[k]
 			// ignore the lootbox if value is 0
 			if v != 0 {
 				s = append(s, kv{k, v})
 			}
 		}
 		// sort the list using preference value of each lootbox
-		sort.Slice(s, func(i, j int) bool {
+		sort.SliceStable(s, func(i, j int) bool {
 			// in the order from large to small
 			return s[i].Value > s[j].Value
 		})
@@ -182,7 +196,8 @@ func BordaCount(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid
 	}
 
 	// calculate the Borda score for each candidates
-	for agent, sortedList := range ss {
+	for _, agent := range utils.SortedIDs(ss) {
+		sortedList := ss[agent]
 		usedKeys := make(map[uuid.UUID]bool)
 		for i, kv := range sortedList {
 			score := float64(len(voteCount)) - float64(i) + 1
@@ -193,7 +208,7 @@ func BordaCount(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid
 		// points shared if not explicity ranked
 		remainingKeyNumber := float64(len(voteCount)) - float64(len(sortedList))
 		remainingScore := (1 + remainingKeyNumber) * remainingKeyNumber / 2
-		for key := range voteCount {
+		for _, key := range utils.SortedIDs(voteCount) {
 			if !usedKeys[key] {
 				voteCount[key] += remainingScore / remainingKeyNumber
 			}
@@ -202,7 +217,8 @@ func BordaCount(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid
 
 	// find the winner with highest score
 	var maxScore float64
-	for key, value := range voteCount {
+	for _, key := range utils.SortedIDs(voteCount) {
+		value := voteCount[key]
 		if value > maxScore {
 			winner = key
 			maxScore = value
@@ -220,10 +236,12 @@ func InstantRunoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[u
 	*/
 	//initialise the votes with weights
 	var voteList []map[uuid.UUID]float64
-	for agent, votes := range voteMap {
+	for _, agent := range utils.SortedIDs(voteMap) {
+		votes := voteMap[agent]
 		weight := voteWeight[agent]
 		weightedvotes := make(map[uuid.UUID]float64)
-		for key, value := range votes {
+		for _, key := range utils.SortedIDs(votes) {
+			value := votes[key]
 			weightedvotes[key] = value * weight
 		}
 		voteList = append(voteList, weightedvotes)
@@ -236,7 +254,7 @@ func InstantRunoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[u
 
 	// initialise the map with all candidates
 	for _, preference := range voteList {
-		for key := range preference {
+		for _, key := range utils.SortedIDs(preference) {
 			voteCount[key] = 0
 		}
 	}
@@ -244,7 +262,7 @@ func InstantRunoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[u
 	// loop to eliminate the least number of first-place votes
 	for len(voteCount) > 1 {
 		// reset map with value = 0
-		for key := range voteCount {
+		for _, key := range utils.SortedIDs(voteCount) {
 			voteCount[key] = 0
 		}
 
@@ -252,7 +270,8 @@ func InstantRunoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[u
 		for _, preference := range voteList {
 			var maxScore float64
 			var firstLootBoxChoice uuid.UUID
-			for key, value := range preference {
+			for _, key := range utils.SortedIDs(preference) {
+				value := preference[key]
 				if (value > maxScore) && !eliminateVote[key] {
 					maxScore = value
 					firstLootBoxChoice = key
@@ -264,7 +283,8 @@ func InstantRunoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[u
 		// eliminate the lootbox with least votes
 		var minVotes float64 = math.MaxFloat64
 		var candidateToEliminate uuid.UUID
-		for key, value := range voteCount {
+		for _, key := range utils.SortedIDs(voteCount) {
+			value := voteCount[key]
 			if value < minVotes {
 				minVotes = value
 				candidateToEliminate = key
@@ -275,7 +295,7 @@ func InstantRunoff(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[u
 	}
 
 	// get the final winner
-	for key := range voteCount {
+	for _, key := range utils.SortedIDs(voteCount) {
 		winner = key
 	}
 
@@ -290,10 +310,12 @@ func Approval(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.U
 	*/
 	//initialise the votes with weights
 	var voteList []map[uuid.UUID]float64
-	for agent, votes := range voteMap {
+	for _, agent := range utils.SortedIDs(voteMap) {
+		votes := voteMap[agent]
 		weight := voteWeight[agent]
 		weightedvotes := make(map[uuid.UUID]float64)
-		for key, value := range votes {
+		for _, key := range utils.SortedIDs(votes) {
+			value := votes[key]
 			weightedvotes[key] = value * weight
 		}
 		voteList = append(voteList, weightedvotes)
@@ -304,7 +326,8 @@ func Approval(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.U
 	var winner uuid.UUID
 
 	for _, preference := range voteList {
-		for key, value := range preference {
+		for _, key := range utils.SortedIDs(preference) {
+			value := preference[key]
 			if value > 0 {
 				voteCount[key] += value
 			}
@@ -314,7 +337,8 @@ func Approval(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map[uuid.U
 	// find the lootbox with max score
 	var maxVotes float64
 
-	for lootBox, votes := range voteCount {
+	for _, lootBox := range utils.SortedIDs(voteCount) {
+		votes := voteCount[lootBox]
 		if votes > maxVotes {
 			maxVotes = votes
 			winner = lootBox
@@ -332,10 +356,12 @@ func CopelandScoring(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map
 	*/
 	//initialise the votes with weights
 	voteListMap := make(map[uuid.UUID]map[uuid.UUID]float64)
-	for agent, votes := range voteMap {
+	for _, agent := range utils.SortedIDs(voteMap) {
+		votes := voteMap[agent]
 		weight := voteWeight[agent]
 		weightedvotes := make(map[uuid.UUID]float64)
-		for key, value := range votes {
+		for _, key := range utils.SortedIDs(votes) {
+			value := votes[key]
 			weightedvotes[key] = value * weight
 		}
 		voteListMap[agent] = weightedvotes
@@ -346,9 +372,12 @@ func CopelandScoring(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map
 	scores := make(map[uuid.UUID]float64)
 
 	// iterate the voting
-	for agent, vote := range voteListMap {
-		for candidate1, score1 := range vote {
-			for candidate2, score2 := range vote {
+	for _, agent := range utils.SortedIDs(voteListMap) {
+		vote := voteListMap[agent]
+		for _, candidate1 := range utils.SortedIDs(vote) {
+			score1 := vote[candidate1]
+			for _, candidate2 := range utils.SortedIDs(vote) {
+				score2 := vote[candidate2]
 				// do not compare with itself
 				if candidate1 == candidate2 {
 					continue
@@ -369,7 +398,8 @@ func CopelandScoring(voteMap map[uuid.UUID]map[uuid.UUID]float64, voteWeight map
 	// find the lootbox with the highest score
 	var maxScore float64
 	var maxCandidate uuid.UUID
-	for candidate, score := range scores {
+	for _, candidate := range utils.SortedIDs(scores) {
+		score := scores[candidate]
 		if score > maxScore || maxCandidate == uuid.Nil {
 			maxScore = score
 			maxCandidate = candidate
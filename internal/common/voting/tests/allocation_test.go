@@ -0,0 +1,53 @@
+package voting
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"SOMAS2023/internal/common/voting"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocationFromDist(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	// two riders split the loot between themselves, the third wants it all
+	voters := map[uuid.UUID]voting.IVoter{
+		a: voting.IdVoteMap{a: 0.5, b: 0.5},
+		b: voting.IdVoteMap{a: 0.5, b: 0.5},
+		c: voting.IdVoteMap{c: 1.0},
+	}
+	weights := map[uuid.UUID]float64{a: 1.0, b: 1.0, c: 1.0}
+
+	mean := voting.AllocationFromDist(voters, weights, utils.MeanAllocation)
+	assert.Equal(t, voting.CumulativeDist(voters, weights), mean)
+	assert.InDelta(t, 1.0/3.0, mean[c], 1e-9)
+
+	median := voting.AllocationFromDist(voters, weights, utils.MedianAllocation)
+	assert.InDelta(t, 0.5, median[a], 1e-9)
+	assert.InDelta(t, 0.5, median[b], 1e-9)
+	assert.InDelta(t, 0.0, median[c], 1e-9, "a single voter should not get a share the others do not give it")
+
+	equal := voting.AllocationFromDist(voters, weights, utils.EqualAllocation)
+	assert.Equal(t, map[uuid.UUID]float64{a: 1.0 / 3.0, b: 1.0 / 3.0, c: 1.0 / 3.0}, equal)
+
+	// with no majority for any share the median falls back to the mean
+	selfish := map[uuid.UUID]voting.IVoter{
+		a: voting.IdVoteMap{a: 1.0},
+		b: voting.IdVoteMap{b: 1.0},
+		c: voting.IdVoteMap{c: 1.0},
+	}
+	assert.Equal(t, voting.CumulativeDist(selfish, weights), voting.MedianDist(selfish, weights))
+}
+
+func TestAcceptanceQuorum(t *testing.T) {
+	a, b, c, applicant := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	rankings := map[uuid.UUID]map[uuid.UUID]bool{
+		a: {applicant: true},
+		b: {applicant: false},
+		c: {applicant: false},
+	}
+	weights := map[uuid.UUID]float64{a: 1.0, b: 1.0, c: 1.0}
+	assert.Empty(t, voting.GetAcceptanceRanking(rankings, weights, 0.5))
+	assert.Equal(t, []uuid.UUID{applicant}, voting.GetAcceptanceRanking(rankings, weights, 0.25))
+}
@@ -0,0 +1,34 @@
+package voting
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"SOMAS2023/internal/common/voting"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCouncilFromDist(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	outsider := uuid.New()
+	voters := map[uuid.UUID]voting.IVoter{
+		a: voting.IdVoteMap{c: 0.5, b: 0.5},
+		b: voting.IdVoteMap{c: 1.0},
+		c: voting.IdVoteMap{outsider: 1.0},
+		d: voting.IdVoteMap{d: 0.5, b: 0.5},
+	}
+	weights := map[uuid.UUID]float64{a: 1.0, b: 1.0, c: 1.0, d: 1.0}
+	candidates := utils.SortedIDs(weights)
+
+	council := voting.CouncilFromDist(voters, weights, candidates, 2)
+	assert.ElementsMatch(t, []uuid.UUID{b, c}, council, "the most voted candidates should be elected")
+	assert.Equal(t, c, council[0])
+
+	// seats no one voted for are filled in the order of the candidates
+	council = voting.CouncilFromDist(voters, weights, candidates, 4)
+	assert.Len(t, council, 4)
+	assert.NotContains(t, council, outsider, "only candidates can be elected")
+	assert.Equal(t, d, council[2])
+	assert.Len(t, voting.CouncilFromDist(voters, weights, candidates, 10), 4)
+}
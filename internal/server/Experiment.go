@@ -0,0 +1,366 @@
+package server
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+const ExperimentResultsFileName = "results.csv"
+const ExperimentRunsFileName = "runs.csv"
+
+// Experiment describes a batch of simulations. Every parameter set is played once per seed.
+// Parameters are keyed by their SimConfig YAML names, for example:
+//
+//	iterations: 3
+//	seeds: 1-10
+//	base:
+//	  round_iterations: 50
+//	grid:
+//	  limbo_energy_penalty: [-0.1, -0.5]
+//	  vote_action: [plurality, borda_count]
+//	runs:
+//	  - team_counts: {team1: 10, team5: 10}
+//	  - team_counts: {team2: 10, team8: 10}
+//
+// The parameter sets are every entry of runs combined with every combination of the grid.
+type Experiment struct {
+	Iterations int              `yaml:"iterations"` // games played by each simulation
+	Seeds      string           `yaml:"seeds"`      // list and/or ranges of seeds, e.g. "1-5,9"
+	Base       map[string]any   `yaml:"base"`       // parameters shared by every simulation
+	Grid       map[string][]any `yaml:"grid"`       // values tried for each parameter
+	Runs       []map[string]any `yaml:"runs"`       // explicit parameter sets
+}
+
+// ParameterSet is the set of parameters that differ between the simulations of an experiment
+type ParameterSet map[string]any
+
+// RunSummary condenses the statistics of the games played by one simulation
+type RunSummary struct {
+	Agents       int     // agents alive at the start of a game
+	SurvivalRate float64 // fraction of them still alive at the end of a game
+	Lifetime     float64 // rounds an agent stayed alive, averaged over the agents
+	Energy       float64 // energy level, averaged over the rounds and agents
+	Points       float64 // points, averaged over the rounds and agents
+}
+
+// ExperimentRun is the outcome of playing one parameter set with one seed
+type ExperimentRun struct {
+	Parameters ParameterSet
+	Seed       int64
+	Summary    RunSummary
+}
+
+// LoadExperiment reads an experiment description from a YAML file
+func LoadExperiment(path string) (Experiment, error) {
+	experiment := Experiment{Iterations: 1, Seeds: "1"}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return experiment, fmt.Errorf("reading experiment file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&experiment); err != nil && !errors.Is(err, io.EOF) {
+		return experiment, fmt.Errorf("parsing experiment file %s: %w", path, err)
+	}
+	if experiment.Iterations <= 0 {
+		return experiment, fmt.Errorf("experiment iterations must be positive, got %d", experiment.Iterations)
+	}
+	return experiment, nil
+}
+
+// ParameterSets expands the grid and the runs of the experiment into the list of parameter sets
+// to simulate, in a deterministic order
+func (e Experiment) ParameterSets() []ParameterSet {
+	parameterSets := make([]ParameterSet, 0)
+	runs := e.Runs
+	if len(runs) == 0 {
+		runs = []map[string]any{{}}
+	}
+	for _, run := range runs {
+		combinations := []ParameterSet{{}}
+		for _, name := range sortedKeys(e.Grid) {
+			extended := make([]ParameterSet, 0, len(combinations)*len(e.Grid[name]))
+			for _, combination := range combinations {
+				for _, value := range e.Grid[name] {
+					parameters := ParameterSet{name: value}
+					for key, existing := range combination {
+						parameters[key] = existing
+					}
+					extended = append(extended, parameters)
+				}
+			}
+			combinations = extended
+		}
+		for _, combination := range combinations {
+			for key, value := range run {
+				combination[key] = value
+			}
+			parameterSets = append(parameterSets, combination)
+		}
+	}
+	return parameterSets
+}
+
+// RunExperiment plays every parameter set of the experiment with every seed, on a pool of workers
+// goroutines. The configuration of each simulation is the base configuration, overridden by the
+// experiment's base parameters and then by the parameter set. If outputOptions selects any output,
+// the files of each simulation are written to <directory>/set_<i>/seed_<seed>.
+func RunExperiment(experiment Experiment, baseConfig utils.SimConfig, outputOptions OutputOptions, workers int) ([]ExperimentRun, error) {
+	seeds, err := utils.ParseSeeds(experiment.Seeds)
+	if err != nil {
+		return nil, err
+	}
+	baseConfig, err = baseConfig.WithParameters(experiment.Base)
+	if err != nil {
+		return nil, fmt.Errorf("experiment base: %w", err)
+	}
+
+	parameterSets := experiment.ParameterSets()
+	configs := make([]utils.SimConfig, len(parameterSets))
+	for i, parameters := range parameterSets {
+		// check every parameter set before running anything
+		if configs[i], err = baseConfig.WithParameters(parameters); err != nil {
+			return nil, fmt.Errorf("parameter set %d: %w", i, err)
+		}
+		if _, err := GetTeamCounts(configs[i]); err != nil {
+			return nil, fmt.Errorf("parameter set %d: %w", i, err)
+		}
+	}
+
+	runs := make([]ExperimentRun, 0, len(parameterSets)*len(seeds))
+	simulations := make([]Simulation, 0, len(parameterSets)*len(seeds))
+	for i, parameters := range parameterSets {
+		for _, seed := range seeds {
+			config := configs[i]
+			config.Seed = seed
+			runOutputOptions := outputOptions
+			runOutputOptions.Directory = filepath.Join(outputOptions.Directory, fmt.Sprintf("set_%d", i), fmt.Sprintf("seed_%d", seed))
+			simulations = append(simulations, Simulation{
+				Iterations: experiment.Iterations,
+				Config:     config,
+				Output:     runOutputOptions,
+			})
+			runs = append(runs, ExperimentRun{Parameters: parameters, Seed: seed})
+		}
+	}
+
+	results, err := RunSimulations(simulations, workers)
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		runs[i].Summary = results[i].Summary
+	}
+	return runs, nil
+}
+
+// SummariseRun reduces the game states of a simulation to a few figures, building on CalculateStatistics
+func SummariseRun(gameStates [][]GameStateDump) RunSummary {
+	summary := RunSummary{}
+	if len(gameStates) == 0 {
+		return summary
+	}
+
+	survivalRate := 0.0
+	for _, game := range gameStates {
+		if len(game) == 0 || len(game[0].Agents) == 0 {
+			continue
+		}
+		summary.Agents = max(summary.Agents, len(game[0].Agents))
+		survivalRate += float64(len(game[len(game)-1].Agents)) / float64(len(game[0].Agents))
+	}
+	summary.SurvivalRate = survivalRate / float64(len(gameStates))
+
+	average := CalculateStatistics(gameStates).Average
+	summary.Lifetime = meanOverAgents(average.AgentLifetime)
+	summary.Energy = meanOverAgents(average.AgentEnergyAverage)
+	summary.Points = meanOverAgents(average.AgentPointsAverage)
+	return summary
+}
+
+func meanOverAgents(values map[uuid.UUID]float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, id := range utils.SortedIDs(values) {
+		sum += values[id]
+	}
+	return sum / float64(len(values))
+}
+
+// ExperimentResult aggregates the runs of one parameter set over every seed
+type ExperimentResult struct {
+	Parameters ParameterSet
+	Seeds      int
+	Mean       RunSummary
+	StdDev     RunSummary
+}
+
+// AggregateRuns groups the runs by parameter set (keeping the order of the experiment) and
+// computes the mean and standard deviation of each figure over the seeds
+func AggregateRuns(runs []ExperimentRun) []ExperimentResult {
+	results := make([]ExperimentResult, 0)
+	groups := make(map[string][]RunSummary)
+	for _, run := range runs {
+		key := run.Parameters.String()
+		if _, ok := groups[key]; !ok {
+			results = append(results, ExperimentResult{Parameters: run.Parameters})
+		}
+		groups[key] = append(groups[key], run.Summary)
+	}
+
+	for i := range results {
+		summaries := groups[results[i].Parameters.String()]
+		results[i].Seeds = len(summaries)
+		results[i].Mean, results[i].StdDev = meanAndStdDev(summaries)
+	}
+	return results
+}
+
+func meanAndStdDev(summaries []RunSummary) (RunSummary, RunSummary) {
+	figures := func(summary RunSummary) []float64 {
+		return []float64{float64(summary.Agents), summary.SurvivalRate, summary.Lifetime, summary.Energy, summary.Points}
+	}
+	n := float64(len(summaries))
+	sums := make([]float64, 5)
+	squares := make([]float64, 5)
+	for _, summary := range summaries {
+		for i, value := range figures(summary) {
+			sums[i] += value
+			squares[i] += value * value
+		}
+	}
+	mean := make([]float64, 5)
+	stdDev := make([]float64, 5)
+	for i := range sums {
+		mean[i] = sums[i] / n
+		stdDev[i] = math.Sqrt(math.Max(squares[i]/n-mean[i]*mean[i], 0))
+	}
+	toSummary := func(values []float64) RunSummary {
+		return RunSummary{
+			Agents:       int(math.Round(values[0])),
+			SurvivalRate: values[1],
+			Lifetime:     values[2],
+			Energy:       values[3],
+			Points:       values[4],
+		}
+	}
+	return toSummary(mean), toSummary(stdDev)
+}
+
+// String formats the parameter set as name=value pairs sorted by name
+func (p ParameterSet) String() string {
+	pairs := make([]string, 0, len(p))
+	for _, name := range sortedKeys(p) {
+		pairs = append(pairs, name+"="+FormatParameter(p[name]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// FormatParameter formats a parameter value for the results table, maps (e.g. team_counts) are
+// written as sorted key:value pairs
+func FormatParameter(value any) string {
+	if values, ok := value.(map[string]any); ok {
+		pairs := make([]string, 0, len(values))
+		for _, key := range sortedKeys(values) {
+			pairs = append(pairs, fmt.Sprintf("%s:%v", key, values[key]))
+		}
+		return strings.Join(pairs, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// ParameterNames returns the names of every parameter varied by the runs, sorted
+func ParameterNames(parameterSets []ParameterSet) []string {
+	names := make(map[string]bool)
+	for _, parameters := range parameterSets {
+		for name := range parameters {
+			names[name] = true
+		}
+	}
+	return sortedKeys(names)
+}
+
+// WriteExperimentResults writes the aggregated results (one row per parameter set) and the
+// individual runs (one row per parameter set and seed) as CSV files
+func WriteExperimentResults(directory string, runs []ExperimentRun) error {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	parameterSets := make([]ParameterSet, len(runs))
+	for i, run := range runs {
+		parameterSets[i] = run.Parameters
+	}
+	names := ParameterNames(parameterSets)
+	formatFloat := func(value float64) string { return strconv.FormatFloat(value, 'f', 4, 64) }
+	parameterColumns := func(parameters ParameterSet) []string {
+		columns := make([]string, len(names))
+		for i, name := range names {
+			if value, ok := parameters[name]; ok {
+				columns[i] = FormatParameter(value)
+			}
+		}
+		return columns
+	}
+
+	resultRows := [][]string{append(append([]string{}, names...),
+		"seeds", "agents", "survival_rate", "survival_rate_std", "lifetime", "lifetime_std",
+		"energy", "energy_std", "points", "points_std")}
+	for _, result := range AggregateRuns(runs) {
+		resultRows = append(resultRows, append(parameterColumns(result.Parameters),
+			strconv.Itoa(result.Seeds), strconv.Itoa(result.Mean.Agents),
+			formatFloat(result.Mean.SurvivalRate), formatFloat(result.StdDev.SurvivalRate),
+			formatFloat(result.Mean.Lifetime), formatFloat(result.StdDev.Lifetime),
+			formatFloat(result.Mean.Energy), formatFloat(result.StdDev.Energy),
+			formatFloat(result.Mean.Points), formatFloat(result.StdDev.Points)))
+	}
+	if err := writeCSV(filepath.Join(directory, ExperimentResultsFileName), resultRows); err != nil {
+		return err
+	}
+
+	runRows := [][]string{append(append([]string{}, names...),
+		"seed", "agents", "survival_rate", "lifetime", "energy", "points")}
+	for _, run := range runs {
+		runRows = append(runRows, append(parameterColumns(run.Parameters),
+			strconv.FormatInt(run.Seed, 10), strconv.Itoa(run.Summary.Agents),
+			formatFloat(run.Summary.SurvivalRate), formatFloat(run.Summary.Lifetime),
+			formatFloat(run.Summary.Energy), formatFloat(run.Summary.Points)))
+	}
+	return writeCSV(filepath.Join(directory, ExperimentRunsFileName), runRows)
+}
+
+func writeCSV(path string, rows [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
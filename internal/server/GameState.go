@@ -2,7 +2,7 @@ package server
 
 import (
 	"SOMAS2023/internal/common/objects"
-	"math/rand"
+	"SOMAS2023/internal/common/utils"
 	"slices"
 
 	"github.com/google/uuid"
@@ -16,8 +16,13 @@ func (s *Server) GetLootBoxes() map[uuid.UUID]objects.ILootBox {
 	return s.lootBoxes
 }
 
+// GetAudi returns the first audi (in the order of their IDs), for agents that only look out for one audi
 func (s *Server) GetAudi() objects.IAudi {
-	return s.audi
+	return s.audisInOrder()[0]
+}
+
+func (s *Server) GetAudis() map[uuid.UUID]objects.IAudi {
+	return s.audis
 }
 
 // get a map of megaBikeIDs mapping to the ids of all Bikers that are trying to join it
@@ -25,7 +30,8 @@ func (s *Server) GetJoiningRequests(inLimbo []uuid.UUID) map[uuid.UUID][]uuid.UU
 	// iterate over all agents, if their onBike is false add to the map their id in correspondance of that of their desired bike
 	bikeRequests := make(map[uuid.UUID][]uuid.UUID)
 
-	for agentID, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
+		agentID := agent.GetID()
 		// don't process joining requests of agents in limbo
 		if !agent.GetBikeStatus() && !slices.Contains(inLimbo, agentID) {
 			bike := agent.GetBike()
@@ -41,13 +47,46 @@ func (s *Server) GetJoiningRequests(inLimbo []uuid.UUID) map[uuid.UUID][]uuid.UU
 
 // GetRandomBikeId returns the ID of a random bike.
 func (s *Server) GetRandomBikeId() uuid.UUID {
-	i, targetI := 0, rand.Intn(len(s.megaBikes))
-	// Go doesn't have a sensible way to do this...
-	for id := range s.megaBikes {
-		if i == targetI {
-			return id
-		}
-		i++
+	if len(s.megaBikes) == 0 {
+		panic("no bikes")
+	}
+	return utils.SortedIDs(s.megaBikes)[s.rng.Intn(len(s.megaBikes))]
+}
+
+// agentsInOrder returns the agents sorted by ID, maps are iterated in a random order which
+// would make the outcome of a round depend on more than the seed of the simulation
+func (s *Server) agentsInOrder() []objects.IBaseBiker {
+	agentMap := s.GetAgentMap()
+	agents := make([]objects.IBaseBiker, 0, len(agentMap))
+	for _, id := range utils.SortedIDs(agentMap) {
+		agents = append(agents, agentMap[id])
+	}
+	return agents
+}
+
+// megaBikesInOrder returns the mega bikes sorted by ID
+func (s *Server) megaBikesInOrder() []objects.IMegaBike {
+	megaBikes := make([]objects.IMegaBike, 0, len(s.megaBikes))
+	for _, id := range utils.SortedIDs(s.megaBikes) {
+		megaBikes = append(megaBikes, s.megaBikes[id])
+	}
+	return megaBikes
+}
+
+// lootBoxesInOrder returns the lootboxes sorted by ID
+func (s *Server) lootBoxesInOrder() []objects.ILootBox {
+	lootBoxes := make([]objects.ILootBox, 0, len(s.lootBoxes))
+	for _, id := range utils.SortedIDs(s.lootBoxes) {
+		lootBoxes = append(lootBoxes, s.lootBoxes[id])
+	}
+	return lootBoxes
+}
+
+// audisInOrder returns the audis sorted by ID
+func (s *Server) audisInOrder() []objects.IAudi {
+	audis := make([]objects.IAudi, 0, len(s.audis))
+	for _, id := range utils.SortedIDs(s.audis) {
+		audis = append(audis, s.audis[id])
 	}
-	panic("no bikes")
+	return audis
 }
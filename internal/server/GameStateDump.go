@@ -2,9 +2,12 @@ package server
 
 import (
 	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/physics"
 	"SOMAS2023/internal/common/utils"
+	"encoding/json"
 	"maps"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -15,14 +18,25 @@ type GameStateDump struct {
 	Agents    map[uuid.UUID]AgentDump   `json:"agents"`
 	Bikes     map[uuid.UUID]BikeDump    `json:"bikes"`
 	LootBoxes map[uuid.UUID]LootBoxDump `json:"loot_boxes"`
-	Audi      AudiDump                  `json:"audi"`
+	Audi      AudiDump                  `json:"audi"` // the first audi, see GetAudi
+	Audis     map[uuid.UUID]AudiDump    `json:"audis"`
+	// how the lootboxes looted in the last round were shared
+	LootShares []LootShareDump `json:"loot_shares"`
+	// sanctions imposed in the last round
+	Sanctions []SanctionDump `json:"sanctions"`
+	// edges of the grid, distances in proximity queries are measured across them
+	Boundary physics.Boundary `json:"boundary"`
+	// spatial indexes answering the proximity queries of the agents, rebuilt when a dump is loaded
+	lootBoxIndex  *physics.SpatialIndex
+	megaBikeIndex *physics.SpatialIndex
 }
 
 type PhysicsObjectDump struct {
-	ID            uuid.UUID           `json:"-"`
-	PhysicalState utils.PhysicalState `json:"physical_state"`
-	Orientation   float64             `json:"orientation"`
-	Force         float64             `json:"force"`
+	ID               uuid.UUID           `json:"-"`
+	PhysicalState    utils.PhysicalState `json:"physical_state"`
+	PreviousPosition utils.Coordinates   `json:"-"`
+	Orientation      float64             `json:"orientation"`
+	Force            float64             `json:"force"`
 }
 
 type BikeDump struct {
@@ -31,6 +45,17 @@ type BikeDump struct {
 	AgentIDs   []uuid.UUID      `json:"agent_ids"`
 	Governance utils.Governance `json:"governance"`
 	Ruler      uuid.UUID        `json:"ruler"`
+	Council    []uuid.UUID      `json:"council"`
+	MaxTurn    float64          `json:"max_turn"`
+	Shield     float64          `json:"shield"`
+	FinePool   float64          `json:"fine_pool"`
+	// rules of the bike and rounds its ruler has served
+	Constitution objects.Constitution `json:"constitution"`
+	RulerTenure  int                  `json:"ruler_tenure"`
+	RulerTerms   int                  `json:"ruler_terms"`
+	CouncilTerms map[uuid.UUID]int    `json:"council_terms,omitempty"`
+	// elections held on the bike during the current game
+	Elections []ElectionDump `json:"elections"`
 }
 
 type AgentDump struct {
@@ -53,23 +78,90 @@ type LootBoxDump struct {
 	TotalResources float64      `json:"total_resources"`
 	Colour         utils.Colour `json:"-"`
 	ColourString   string       `json:"colour"`
+	TimeToLive     int          `json:"time_to_live"`
 }
 
 type AudiDump struct {
 	PhysicsObjectDump
 	ID         uuid.UUID `json:"id"`
 	TargetBike uuid.UUID `json:"target_bike"`
+	Cooldown   int       `json:"cooldown"`
+}
+
+// LootShareDump records how a lootbox was shared between the bikes that reached it
+type LootShareDump struct {
+	LootBox     uuid.UUID             `json:"loot_box"`
+	Contestants map[uuid.UUID]float64 `json:"contestants"` // time of impact of every bike that reached the lootbox
+	Shares      map[uuid.UUID]float64 `json:"shares"`      // fraction of the lootbox each bike got
+}
+
+// SanctionDump records a sanction imposed on an agent
+type SanctionDump struct {
+	Round    int                `json:"round"`
+	Bike     uuid.UUID          `json:"bike"`
+	Agent    uuid.UUID          `json:"agent"`
+	Sanction utils.SanctionType `json:"sanction"`
+	Offence  int                `json:"offence"` // offences of the agent so far, including this one
+}
+
+// ElectionReason is why the ruler of a bike was elected
+type ElectionReason string
+
+const (
+	FoundingElection    ElectionReason = "founding"    // the bike got its first riders at the start of the game
+	BoardingElection    ElectionReason = "boarding"    // an empty bike got new riders, or a council got seats to fill
+	DeathElection       ElectionReason = "death"       // the ruler, or a council member, died
+	KickoutElection     ElectionReason = "kickout"     // the ruler, or a council member, was kicked out
+	LeavingElection     ElectionReason = "leaving"     // the ruler, or a council member, left the bike
+	ReferendumElection  ElectionReason = "referendum"  // the bike switched to a ruler-led governance
+	TermElection        ElectionReason = "term"        // the term of the ruler ended
+	ImpeachmentElection ElectionReason = "impeachment" // the ruler was impeached
+)
+
+// ElectionDump records the election of the ruler, or of the council, of a bike
+type ElectionDump struct {
+	Round      int              `json:"round"`
+	Governance utils.Governance `json:"governance"`
+	Previous   uuid.UUID        `json:"previous"` // ruler before the election, possibly the same
+	Ruler      uuid.UUID        `json:"ruler"`
+	Council    []uuid.UUID      `json:"council,omitempty"` // members elected to the council, in order
+	Reason     ElectionReason   `json:"reason"`
+}
+
+func newLootShareDump(lootBox uuid.UUID, contestants []objects.LootContestant, shares map[uuid.UUID]float64) LootShareDump {
+	dump := LootShareDump{
+		LootBox:     lootBox,
+		Contestants: make(map[uuid.UUID]float64, len(contestants)),
+		Shares:      maps.Clone(shares),
+	}
+	for _, contestant := range contestants {
+		dump.Contestants[contestant.Bike.GetID()] = contestant.TimeOfImpact
+	}
+	return dump
 }
 
 func newPhysicsObjectDump(physicsObject objects.IPhysicsObject) PhysicsObjectDump {
 	return PhysicsObjectDump{
-		ID:            physicsObject.GetID(),
-		PhysicalState: physicsObject.GetPhysicalState(),
-		Orientation:   physicsObject.GetOrientation(),
-		Force:         physicsObject.GetForce(),
+		ID:               physicsObject.GetID(),
+		PhysicalState:    physicsObject.GetPhysicalState(),
+		PreviousPosition: physicsObject.GetPreviousPosition(),
+		Orientation:      physicsObject.GetOrientation(),
+		Force:            physicsObject.GetForce(),
 	}
 }
 
+// councilTerms returns the consecutive terms of every council member of a bike, nil without a council
+func councilTerms(bike objects.IMegaBike) map[uuid.UUID]int {
+	if len(bike.GetCouncil()) == 0 {
+		return nil
+	}
+	terms := make(map[uuid.UUID]int, len(bike.GetCouncil()))
+	for _, member := range bike.GetCouncil() {
+		terms[member] = bike.GetCouncilTerms(member)
+	}
+	return terms
+}
+
 func (s *Server) NewGameStateDump(iteration int) GameStateDump {
 	agents := make(map[uuid.UUID]AgentDump, len(s.GetAgentMap()))
 	for id, agent := range s.GetAgentMap() {
@@ -109,6 +201,15 @@ func (s *Server) NewGameStateDump(iteration int) GameStateDump {
 			AgentIDs:          agentIDs,
 			Governance:        bike.GetGovernance(),
 			Ruler:             bike.GetRuler(),
+			Council:           slices.Clone(bike.GetCouncil()),
+			MaxTurn:           bike.GetMaxTurn(),
+			Shield:            bike.GetShield(),
+			FinePool:          bike.GetFinePool(),
+			Constitution:      bike.GetConstitution(),
+			RulerTenure:       bike.GetRulerTenure(),
+			RulerTerms:        bike.GetRulerTerms(),
+			CouncilTerms:      councilTerms(bike),
+			Elections:         s.GetElectionHistory(id),
 		}
 	}
 
@@ -119,18 +220,54 @@ func (s *Server) NewGameStateDump(iteration int) GameStateDump {
 			TotalResources:    lootBox.GetTotalResources(),
 			Colour:            lootBox.GetColour(),
 			ColourString:      lootBox.GetColour().String(),
+			TimeToLive:        lootBox.GetTimeToLive(),
+		}
+	}
+
+	audis := make(map[uuid.UUID]AudiDump, len(s.audis))
+	for id, audi := range s.audis {
+		audis[id] = AudiDump{
+			PhysicsObjectDump: newPhysicsObjectDump(audi),
+			ID:                audi.GetID(),
+			TargetBike:        audi.GetTargetID(),
+			Cooldown:          audi.GetCooldown(),
 		}
 	}
 
 	return GameStateDump{
-		Iteration: iteration,
-		Agents:    agents,
-		Bikes:     bikes,
-		LootBoxes: lootBoxes,
-		Audi: AudiDump{
-			PhysicsObjectDump: newPhysicsObjectDump(s.audi),
-			ID:                s.audi.GetID(),
-			TargetBike:        s.audi.GetTargetID(),
-		},
+		Iteration:     iteration,
+		Agents:        agents,
+		Bikes:         bikes,
+		LootBoxes:     lootBoxes,
+		Audi:          audis[s.GetAudi().GetID()],
+		Audis:         audis,
+		LootShares:    append([]LootShareDump{}, s.lootShares...),
+		Sanctions:     append([]SanctionDump{}, s.sanctions...),
+		Boundary:      s.physicsEngine.Boundary,
+		lootBoxIndex:  newSpatialIndex(s.lootBoxes, s.physicsEngine.Boundary, s.config.CollisionThreshold),
+		megaBikeIndex: newSpatialIndex(s.megaBikes, s.physicsEngine.Boundary, s.config.CollisionThreshold),
+	}
+}
+
+// UnmarshalJSON reads a game state back from a game dump, and rebuilds the spatial indexes of its proximity queries.
+// The size of their cells only affects how fast they are, so the default collision threshold is used.
+func (gs *GameStateDump) UnmarshalJSON(data []byte) error {
+	// the fields are decoded without this method
+	type gameStateDump GameStateDump
+	if err := json.Unmarshal(data, (*gameStateDump)(gs)); err != nil {
+		return err
+	}
+	gs.lootBoxIndex = newSpatialIndex(gs.LootBoxes, gs.Boundary, utils.CollisionThreshold)
+	gs.megaBikeIndex = newSpatialIndex(gs.Bikes, gs.Boundary, utils.CollisionThreshold)
+	return nil
+}
+
+// newSpatialIndex indexes the positions of the objects, cells are the size of a collision so that
+// collision checks only need to look at the neighbouring cells
+func newSpatialIndex[T objects.IPhysicsObject](physicsObjects map[uuid.UUID]T, boundary physics.Boundary, cellSize float64) *physics.SpatialIndex {
+	index := physics.NewSpatialIndex(cellSize, boundary)
+	for _, id := range utils.SortedIDs(physicsObjects) {
+		index.Insert(id, physicsObjects[id].GetPosition())
 	}
+	return index
 }
@@ -15,6 +15,10 @@ func (o PhysicsObjectDump) SetPhysicalState(state utils.PhysicalState) {
 	panic(bannedFunctionErrorMessage)
 }
 
+func (o PhysicsObjectDump) SetOrientation(float64) {
+	panic(bannedFunctionErrorMessage)
+}
+
 func (o PhysicsObjectDump) UpdateForce() {
 	panic(bannedFunctionErrorMessage)
 }
@@ -27,6 +31,10 @@ func (o PhysicsObjectDump) CheckForCollision(objects.IPhysicsObject) bool {
 	panic(bannedFunctionErrorMessage)
 }
 
+func (o PhysicsObjectDump) TimeOfImpact(objects.IPhysicsObject) (float64, bool) {
+	panic(bannedFunctionErrorMessage)
+}
+
 func (a AgentDump) GetAllMessages([]objects.IBaseBiker) []messaging.IMessage[objects.IBaseBiker] {
 	panic(bannedFunctionErrorMessage)
 }
@@ -181,6 +189,46 @@ func (a AgentDump) ResetPoints() {
 	panic(bannedFunctionErrorMessage)
 }
 
+func (a AgentDump) DecideShieldContribution() float64 {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) DecideLootClaim(uuid.UUID, []uuid.UUID) float64 {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) PetitionReferendum() bool {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) VoteGovernance() voting.GovernanceVote {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) ProposeAmendment(objects.Constitution) (objects.Constitution, bool) {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) VoteAmendment(objects.Constitution, objects.Constitution) bool {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) DecideImpeachment() bool {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) VoteCouncil() voting.IdVoteMap {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) VoteForSanction() map[uuid.UUID]int {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AgentDump) DecideSanctions() []uuid.UUID {
+	panic(bannedFunctionErrorMessage)
+}
+
 func (b BikeDump) AddAgent(objects.IBaseBiker) {
 	panic(bannedFunctionErrorMessage)
 }
@@ -205,6 +253,46 @@ func (b BikeDump) SetRuler(uuid.UUID) {
 	panic(bannedFunctionErrorMessage)
 }
 
+func (b BikeDump) AddShield(float64) {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (b BikeDump) AddFinePool(float64) {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (b BikeDump) SetConstitution(objects.Constitution) {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (b BikeDump) SetCouncil([]uuid.UUID) {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (b BikeDump) FillCouncil([]uuid.UUID) {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (b BikeDump) CouncilKickOut(map[uuid.UUID]float64) []uuid.UUID {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (b BikeDump) IncrementRulerTenure() {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (l LootBoxDump) Age() {
+	panic(bannedFunctionErrorMessage)
+}
+
 func (a AudiDump) UpdateGameState(objects.IGameState) {
 	panic(bannedFunctionErrorMessage)
 }
+
+func (a AudiDump) SetStrategy(objects.IAudiStrategy) {
+	panic(bannedFunctionErrorMessage)
+}
+
+func (a AudiDump) SetCooldown(int) {
+	panic(bannedFunctionErrorMessage)
+}
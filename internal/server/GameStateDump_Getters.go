@@ -36,6 +36,32 @@ func (gs GameStateDump) GetAudi() objects.IAudi {
 	return gs.Audi
 }
 
+func (gs GameStateDump) GetAudis() map[uuid.UUID]objects.IAudi {
+	audis := make(map[uuid.UUID]objects.IAudi, len(gs.Audis))
+	for id, audi := range gs.Audis {
+		audis[id] = audi
+	}
+	return audis
+}
+
+func (gs GameStateDump) GetLootBoxesWithin(position utils.Coordinates, radius float64) []uuid.UUID {
+	return gs.lootBoxIndex.Within(position, radius)
+}
+
+func (gs GameStateDump) GetMegaBikesWithin(position utils.Coordinates, radius float64) []uuid.UUID {
+	return gs.megaBikeIndex.Within(position, radius)
+}
+
+func (gs GameStateDump) GetNearestLootBoxes(position utils.Coordinates, k int) []uuid.UUID {
+	return gs.lootBoxIndex.Nearest(position, k, nil)
+}
+
+func (gs GameStateDump) GetNearestLootBoxesOfColour(position utils.Coordinates, k int, colour utils.Colour) []uuid.UUID {
+	return gs.lootBoxIndex.Nearest(position, k, func(id uuid.UUID) bool {
+		return gs.LootBoxes[id].Colour == colour
+	})
+}
+
 func (o PhysicsObjectDump) GetID() uuid.UUID {
 	return o.ID
 }
@@ -44,6 +70,10 @@ func (o PhysicsObjectDump) GetPosition() utils.Coordinates {
 	return o.PhysicalState.Position
 }
 
+func (o PhysicsObjectDump) GetPreviousPosition() utils.Coordinates {
+	return o.PreviousPosition
+}
+
 func (o PhysicsObjectDump) GetVelocity() float64 {
 	return o.PhysicalState.Velocity
 }
@@ -112,6 +142,38 @@ func (b BikeDump) GetRuler() uuid.UUID {
 	return b.Ruler
 }
 
+func (b BikeDump) GetMaxTurn() float64 {
+	return b.MaxTurn
+}
+
+func (b BikeDump) GetShield() float64 {
+	return b.Shield
+}
+
+func (b BikeDump) GetFinePool() float64 {
+	return b.FinePool
+}
+
+func (b BikeDump) GetConstitution() objects.Constitution {
+	return b.Constitution
+}
+
+func (b BikeDump) GetRulerTenure() int {
+	return b.RulerTenure
+}
+
+func (b BikeDump) GetRulerTerms() int {
+	return b.RulerTerms
+}
+
+func (b BikeDump) GetCouncil() []uuid.UUID {
+	return b.Council
+}
+
+func (b BikeDump) GetCouncilTerms(member uuid.UUID) int {
+	return b.CouncilTerms[member]
+}
+
 func (l LootBoxDump) GetTotalResources() float64 {
 	return l.TotalResources
 }
@@ -120,6 +182,14 @@ func (l LootBoxDump) GetColour() utils.Colour {
 	return l.Colour
 }
 
+func (l LootBoxDump) GetTimeToLive() int {
+	return l.TimeToLive
+}
+
 func (a AudiDump) GetTargetID() uuid.UUID {
 	return a.TargetBike
 }
+
+func (a AudiDump) GetCooldown() int {
+	return a.Cooldown
+}
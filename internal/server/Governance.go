@@ -4,6 +4,8 @@ import (
 	"SOMAS2023/internal/common/objects"
 	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/common/voting"
+	"maps"
+	"slices"
 
 	"github.com/google/uuid"
 )
@@ -17,18 +19,48 @@ func (s *Server) RunRulerAction(bike objects.IMegaBike) uuid.UUID {
 	return direction
 }
 
+// RulerElection counts the votes with the voting method of the simulation config, bikes use the one of their constitution
 func (s *Server) RulerElection(agents []objects.IBaseBiker, governance utils.Governance) uuid.UUID {
-	// TODO: need extra input "voteWeight". For now, we just initialise a unit weight for each agent
+	return s.rulerElection(agents, governance, uuid.Nil, s.config.VoteAction)
+}
+
+// rulerElection elects a ruler amongst the agents, the votes for the excluded agent are discarded. Voters left
+// without a candidate abstain, and if every voter abstains the first eligible agent becomes the ruler.
+func (s *Server) rulerElection(agents []objects.IBaseBiker, governance utils.Governance, excluded uuid.UUID, method utils.VoteMethod) uuid.UUID {
+	// every agent has a unit weight, unless a sanction reduced it
 	votes := make(map[uuid.UUID]voting.IdVoteMap, len(agents))
 	voteWeight := make(map[uuid.UUID]float64)
+	weights := s.riderWeights(agents)
 	for _, agent := range agents {
-		voteWeight[agent.GetID()] = 1
+		var vote voting.IdVoteMap
 		switch governance {
 		case utils.Dictatorship:
-			votes[agent.GetID()] = agent.VoteDictator()
+			vote = agent.VoteDictator()
 		case utils.Leadership:
-			votes[agent.GetID()] = agent.VoteLeader()
+			vote = agent.VoteLeader()
+		}
+		if excluded != uuid.Nil {
+			vote = maps.Clone(vote)
+			delete(vote, excluded)
+			total := 0.0
+			for _, weight := range vote {
+				total += weight
+			}
+			if total == 0 {
+				continue
+			}
+		}
+		voteWeight[agent.GetID()] = weights[agent.GetID()]
+		votes[agent.GetID()] = vote
+	}
+
+	if len(votes) == 0 {
+		for _, agent := range agents {
+			if agent.GetID() != excluded {
+				return agent.GetID()
+			}
 		}
+		return excluded
 	}
 
 	IVotes := make(map[uuid.UUID]voting.IVoter, len(votes))
@@ -36,10 +68,144 @@ func (s *Server) RulerElection(agents []objects.IBaseBiker, governance utils.Gov
 		IVotes[i] = vote
 	}
 
-	ruler := voting.WinnerFromDist(IVotes, voteWeight)
+	ruler := voting.WinnerFromDist(IVotes, voteWeight, method)
 	return ruler
 }
 
+// electRuler elects the ruler of a bike amongst its riders, except the excluded one, with the voting method of
+// its constitution, and records the election in the history of the bike
+func (s *Server) electRuler(bike objects.IMegaBike, governance utils.Governance, reason ElectionReason, excluded uuid.UUID) {
+	previous := bike.GetRuler()
+	ruler := s.rulerElection(bike.GetAgents(), governance, excluded, bike.GetConstitution().DirectionVoting)
+	bike.SetRuler(ruler)
+	s.elections[bike.GetID()] = append(s.elections[bike.GetID()], ElectionDump{
+		Round:      s.round,
+		Governance: governance,
+		Previous:   previous,
+		Ruler:      ruler,
+		Reason:     reason,
+	})
+}
+
+// electCouncil elects the council of a bike amongst its riders for a new term, every rider votes for the riders
+// it wants on the council, and records the election in the history of the bike. The excluded riders only keep
+// their seats if there are not enough other riders to fill the council.
+func (s *Server) electCouncil(bike objects.IMegaBike, reason ElectionReason, excluded []uuid.UUID) {
+	votes, weights := s.councilVotes(bike)
+	eligible, ineligible := make([]uuid.UUID, 0, len(weights)), make([]uuid.UUID, 0)
+	for _, id := range utils.SortedIDs(weights) {
+		if slices.Contains(excluded, id) {
+			ineligible = append(ineligible, id)
+		} else {
+			eligible = append(eligible, id)
+		}
+	}
+	seats := bike.GetConstitution().CouncilSize
+	council := voting.CouncilFromDist(votes, weights, eligible, seats)
+	if len(council) < seats && len(ineligible) != 0 {
+		council = append(council, voting.CouncilFromDist(votes, weights, ineligible, seats-len(council))...)
+	}
+	bike.SetCouncil(council)
+	s.recordCouncilElection(bike, reason)
+}
+
+// fillCouncil elects riders to the vacant seats of the council of a bike, the members still riding it keep their
+// seats and the term of the council goes on
+func (s *Server) fillCouncil(bike objects.IMegaBike, reason ElectionReason) {
+	votes, weights := s.councilVotes(bike)
+	seats := bike.GetConstitution().CouncilSize
+	council := make([]uuid.UUID, 0, seats)
+	for _, member := range bike.GetCouncil() {
+		if s.megaBikeRiders[member] == bike.GetID() && len(council) < seats {
+			council = append(council, member)
+		}
+	}
+	candidates := make([]uuid.UUID, 0, len(weights))
+	for _, id := range utils.SortedIDs(weights) {
+		if !slices.Contains(council, id) {
+			candidates = append(candidates, id)
+		}
+	}
+	council = append(council, voting.CouncilFromDist(votes, weights, candidates, seats-len(council))...)
+	bike.FillCouncil(council)
+	s.recordCouncilElection(bike, reason)
+}
+
+// councilVotes collects the council votes of the riders of a bike and their weights
+func (s *Server) councilVotes(bike objects.IMegaBike) (map[uuid.UUID]voting.IVoter, map[uuid.UUID]float64) {
+	riders := bike.GetAgents()
+	votes := make(map[uuid.UUID]voting.IVoter, len(riders))
+	for _, rider := range riders {
+		votes[rider.GetID()] = rider.VoteCouncil()
+	}
+	return votes, s.riderWeights(riders)
+}
+
+// recordCouncilElection records the council of a bike in its election history
+func (s *Server) recordCouncilElection(bike objects.IMegaBike, reason ElectionReason) {
+	s.elections[bike.GetID()] = append(s.elections[bike.GetID()], ElectionDump{
+		Round:      s.round,
+		Governance: utils.Council,
+		Council:    slices.Clone(bike.GetCouncil()),
+		Reason:     reason,
+	})
+}
+
+// reelectCouncils fills the vacant seats of the councils which lost a member, or which have seats their riders
+// could fill
+func (s *Server) reelectCouncils(reason ElectionReason) {
+	updated := false
+	for _, bike := range s.megaBikesInOrder() {
+		riders := bike.GetAgents()
+		if bike.GetGovernance() != utils.Council || len(riders) == 0 {
+			continue
+		}
+		vacant := len(bike.GetCouncil()) < min(bike.GetConstitution().CouncilSize, len(riders))
+		for _, member := range bike.GetCouncil() {
+			vacant = vacant || s.megaBikeRiders[member] != bike.GetID()
+		}
+		if !vacant {
+			continue
+		}
+		// agents need an updated game state to vote
+		if !updated {
+			s.UpdateGameStates()
+			updated = true
+		}
+		s.fillCouncil(bike, reason)
+	}
+}
+
+// councilWeights gives the council members of a bike a weight of 1 and the other riders a weight of 0
+func councilWeights(bike objects.IMegaBike) map[uuid.UUID]float64 {
+	weights := make(map[uuid.UUID]float64)
+	for _, agent := range bike.GetAgents() {
+		weights[agent.GetID()] = 0.0
+	}
+	for _, member := range bike.GetCouncil() {
+		if _, ok := weights[member]; ok {
+			weights[member] = 1.0
+		}
+	}
+	return weights
+}
+
+// councilMembers returns the council members of a bike who are riding it
+func (s *Server) councilMembers(bike objects.IMegaBike) []objects.IBaseBiker {
+	members := make([]objects.IBaseBiker, 0, len(bike.GetCouncil()))
+	for _, agent := range bike.GetAgents() {
+		if slices.Contains(bike.GetCouncil(), agent.GetID()) {
+			members = append(members, agent)
+		}
+	}
+	return members
+}
+
+// GetElectionHistory returns the elections held on a bike during the current game, oldest first
+func (s *Server) GetElectionHistory(bikeID uuid.UUID) []ElectionDump {
+	return slices.Clone(s.elections[bikeID])
+}
+
 func (s *Server) RunDemocraticAction(bike objects.IMegaBike, weights map[uuid.UUID]float64) uuid.UUID {
 	// map of the proposed lootboxes by bike (for each bike a list of lootbox proposals is made, with one lootbox proposed by each agent on the bike)
 	agents := bike.GetAgents()
@@ -65,9 +231,204 @@ func (s *Server) RunDemocraticAction(bike objects.IMegaBike, weights map[uuid.UU
 	}
 
 	// ---------------------------VOTING ROUTINE - STEP 3 --------------
-	direction := s.GetWinningDirection(finalVotes, weights)
+	direction := s.winningDirection(finalVotes, s.sanctionedWeights(weights), bike.GetConstitution().DirectionVoting)
 	if _, ok := s.lootBoxes[direction]; !ok {
 		panic("agents voted on a non-existent lootbox")
 	}
 	return direction
 }
+
+// RunReferendums lets the riders of every bike vote on its governance, every referendum_period rounds or when
+// at least referendum_petition of them ask for it. The bike switches to the winning governance if it gets the
+// supermajority its constitution requires, and elects a new ruler if the new governance needs one.
+func (s *Server) RunReferendums() {
+	if s.config.ReferendumPeriod == 0 && s.config.ReferendumPetition == 0 {
+		return
+	}
+	scheduled := s.config.ReferendumPeriod > 0 && s.round > 0 && s.round%s.config.ReferendumPeriod == 0
+	for _, bike := range s.megaBikesInOrder() {
+		riders := bike.GetAgents()
+		if len(riders) == 0 || (!scheduled && !s.petitioned(riders)) {
+			continue
+		}
+
+		// the ballots of the riders whose vote weight is reduced by a sanction count for less
+		weights := s.riderWeights(riders)
+		votes := make([]voting.GovernanceVote, 0, len(riders))
+		total := 0.0
+		for _, rider := range riders {
+			votes = append(votes, weightedGovernanceVote(rider.VoteGovernance(), weights[rider.GetID()]))
+			total += weights[rider.GetID()]
+		}
+		winner, err := voting.WinnerFromGovernance(votes)
+		if err != nil {
+			s.logf("referendum on bike %s is void: %v\n", bike.GetID(), err)
+			continue
+		}
+		support := 0.0
+		for _, vote := range votes {
+			support += vote[winner]
+		}
+		if winner == bike.GetGovernance() || support < bike.GetConstitution().GovernanceSupermajority*total {
+			continue
+		}
+
+		s.logf("bike %s switches from governance %d to %d\n", bike.GetID(), bike.GetGovernance(), winner)
+		bike.SetGovernance(winner)
+		bike.SetCouncil(nil)
+		switch winner {
+		case utils.Democracy:
+			bike.SetRuler(uuid.Nil)
+		case utils.Council:
+			bike.SetRuler(uuid.Nil)
+			s.electCouncil(bike, ReferendumElection, nil)
+		default:
+			s.electRuler(bike, winner, ReferendumElection, uuid.Nil)
+		}
+	}
+	s.UpdateGameStates()
+}
+
+// RunAmendments puts the amendments to the constitution of every bike its riders propose to their vote, an
+// amendment passes with the supermajority the constitution requires. A dictator amends the constitution by decree.
+func (s *Server) RunAmendments() {
+	amended := false
+	for _, bike := range s.megaBikesInOrder() {
+		riders := bike.GetAgents()
+		if len(riders) == 0 {
+			continue
+		}
+		proposers := riders
+		if bike.GetGovernance() == utils.Dictatorship {
+			ruler, ok := s.GetAgentMap()[bike.GetRuler()]
+			if !ok {
+				continue
+			}
+			proposers = []objects.IBaseBiker{ruler}
+		}
+		for _, proposer := range proposers {
+			constitution := bike.GetConstitution()
+			amendment, ok := proposer.ProposeAmendment(constitution)
+			if !ok || amendment == constitution {
+				continue
+			}
+			if err := amendment.Validate(); err != nil {
+				s.logf("agent %s proposed an invalid amendment: %v\n", proposer.GetID(), err)
+				continue
+			}
+			if bike.GetGovernance() != utils.Dictatorship {
+				weights := s.riderWeights(riders)
+				support, total := 0.0, 0.0
+				for _, rider := range riders {
+					if rider.VoteAmendment(constitution, amendment) {
+						support += weights[rider.GetID()]
+					}
+					total += weights[rider.GetID()]
+				}
+				if support < constitution.AmendmentSupermajority*total {
+					continue
+				}
+			}
+			s.logf("bike %s amends its constitution\n", bike.GetID())
+			bike.SetConstitution(amendment)
+			amended = true
+		}
+	}
+	if amended {
+		s.UpdateGameStates()
+	}
+}
+
+// RunImpeachments lets the riders of every ruler-led bike call a vote of no confidence in their ruler. The ruler
+// is removed if the supermajority of the other riders the constitution requires votes against them, and the
+// riders elect someone else.
+func (s *Server) RunImpeachments() {
+	impeached := false
+	for _, bike := range s.megaBikesInOrder() {
+		gov := bike.GetGovernance()
+		riders := bike.GetAgents()
+		if len(riders) < 2 || (gov != utils.Leadership && gov != utils.Dictatorship) {
+			continue
+		}
+		ruler := bike.GetRuler()
+		weights := s.riderWeights(riders)
+		noConfidence, total := 0.0, 0.0
+		for _, rider := range riders {
+			if rider.GetID() == ruler {
+				continue
+			}
+			if rider.DecideImpeachment() {
+				noConfidence += weights[rider.GetID()]
+			}
+			total += weights[rider.GetID()]
+		}
+		if noConfidence == 0 || noConfidence < bike.GetConstitution().ImpeachmentSupermajority*total {
+			continue
+		}
+
+		s.logf("bike %s impeaches its ruler %s\n", bike.GetID(), ruler)
+		s.electRuler(bike, gov, ImpeachmentElection, ruler)
+		impeached = true
+	}
+	if impeached {
+		s.UpdateGameStates()
+	}
+}
+
+// endRulerTerms elects a new ruler (or council) on the bikes whose ruler has served the term their constitution
+// sets, the ruler (or a council member) can be re-elected unless they have served as many consecutive terms as
+// the constitution allows
+func (s *Server) endRulerTerms() {
+	for _, bike := range s.megaBikesInOrder() {
+		gov := bike.GetGovernance()
+		if len(bike.GetAgents()) == 0 || gov == utils.Democracy {
+			continue
+		}
+		bike.IncrementRulerTenure()
+		constitution := bike.GetConstitution()
+		if constitution.RulerTerm == 0 || bike.GetRulerTenure() < constitution.RulerTerm {
+			continue
+		}
+		if gov == utils.Council {
+			excluded := make([]uuid.UUID, 0)
+			for _, member := range bike.GetCouncil() {
+				if constitution.TermLimit > 0 && bike.GetCouncilTerms(member) >= constitution.TermLimit {
+					excluded = append(excluded, member)
+				}
+			}
+			s.electCouncil(bike, TermElection, excluded)
+			continue
+		}
+		excluded := uuid.Nil
+		if constitution.TermLimit > 0 && bike.GetRulerTerms() >= constitution.TermLimit {
+			excluded = bike.GetRuler()
+		}
+		s.electRuler(bike, gov, TermElection, excluded)
+	}
+}
+
+// weightedGovernanceVote scales a governance vote by the weight of the voter
+func weightedGovernanceVote(vote voting.GovernanceVote, weight float64) voting.GovernanceVote {
+	if weight == 1.0 {
+		return vote
+	}
+	weighted := make(voting.GovernanceVote, len(vote))
+	for governance, share := range vote {
+		weighted[governance] = share * weight
+	}
+	return weighted
+}
+
+// petitioned reports whether enough riders ask for a referendum
+func (s *Server) petitioned(riders []objects.IBaseBiker) bool {
+	if s.config.ReferendumPetition == 0 {
+		return false
+	}
+	petitions := 0
+	for _, rider := range riders {
+		if rider.PetitionReferendum() {
+			petitions++
+		}
+	}
+	return float64(petitions) >= s.config.ReferendumPetition*float64(len(riders))
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+/*
+Agents do not necessarily see the lootboxes as they are. Depending on the simulation config, the resources
+and colour of lootboxes further than the reveal radius from the bike of an agent are hidden or noisy, so
+agents have to go and look (or ask the agents that did) to know what a lootbox is worth.
+*/
+
+// a noisy sighting is drawn once for every bike and lootbox, so that looking again does not average the noise out.
+// Agents off a bike share the sightings of uuid.Nil.
+type sightingKey struct {
+	bike    uuid.UUID
+	lootBox uuid.UUID
+}
+
+type sighting struct {
+	resourceFactor float64      // the resources seen are the actual resources times this factor
+	colour         utils.Colour // the colour seen
+}
+
+// observedBy returns the game state as seen by an agent
+func (s *Server) observedBy(gameState objects.IGameState, agent objects.IBaseBiker) objects.IGameState {
+	dump, ok := gameState.(GameStateDump)
+	if !ok || (s.config.LootBoxResourceObservation == utils.ExactObservation && s.config.LootBoxColourObservation == utils.ExactObservation) {
+		return gameState
+	}
+
+	bikeID := uuid.Nil
+	if agent.GetBikeStatus() {
+		bikeID = agent.GetBike()
+	}
+	bike, onBike := dump.Bikes[bikeID]
+	observed := dump
+	observed.LootBoxes = make(map[uuid.UUID]LootBoxDump, len(dump.LootBoxes))
+	for _, id := range utils.SortedIDs(dump.LootBoxes) {
+		lootBox := dump.LootBoxes[id]
+		revealed := onBike && s.physicsEngine.Boundary.ComputeDistance(bike.GetPosition(), lootBox.GetPosition()) < math.Pow(s.config.LootBoxRevealRadius, 2)
+		if !revealed {
+			lootBox = s.blur(lootBox, bikeID)
+		}
+		observed.LootBoxes[id] = lootBox
+	}
+	return observed
+}
+
+// blur hides or adds noise to the resources and colour of a lootbox seen from afar
+func (s *Server) blur(lootBox LootBoxDump, bikeID uuid.UUID) LootBoxDump {
+	switch s.config.LootBoxResourceObservation {
+	case utils.HiddenObservation:
+		lootBox.TotalResources = utils.HiddenResources
+	case utils.NoisyObservation:
+		lootBox.TotalResources *= s.sighting(bikeID, lootBox).resourceFactor
+	}
+	switch s.config.LootBoxColourObservation {
+	case utils.HiddenObservation:
+		lootBox.Colour = utils.HiddenColour
+	case utils.NoisyObservation:
+		lootBox.Colour = s.sighting(bikeID, lootBox).colour
+	}
+	lootBox.ColourString = lootBox.Colour.String()
+	return lootBox
+}
+
+// sighting returns the noise of the sighting of a lootbox from a bike, drawing it the first time the bike sees it
+func (s *Server) sighting(bikeID uuid.UUID, lootBox LootBoxDump) sighting {
+	key := sightingKey{bike: bikeID, lootBox: lootBox.ID}
+	if seen, ok := s.sightings[key]; ok {
+		return seen
+	}
+	seen := sighting{
+		resourceFactor: math.Max(0, 1+s.rng.NormFloat64()*s.config.LootBoxResourceNoise),
+		colour:         lootBox.Colour,
+	}
+	if s.rng.Float64() < s.config.LootBoxColourNoise {
+		seen.colour = utils.Colour(s.rng.Intn(int(utils.NumOfColours)))
+	}
+	s.sightings[key] = seen
+	return seen
+}
+
+// forgetSightings drops the sightings of the lootboxes that are gone
+func (s *Server) forgetSightings() {
+	for key := range s.sightings {
+		if _, ok := s.lootBoxes[key.lootBox]; !ok {
+			delete(s.sightings, key)
+		}
+	}
+}
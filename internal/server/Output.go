@@ -0,0 +1,117 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const StatisticsFileName = "statistics.xlsx"
+const GameDumpFileName = "game_dump.json"
+
+// OutputOptions controls which result files are written at the end of a run, and where
+type OutputOptions struct {
+	Directory  string
+	Statistics bool // writes the per round statistics spreadsheet
+	GameDump   bool // writes the game state of every round (used by the visualiser)
+	// Log receives the progress messages of the server, nil discards them. Simulations that run
+	// side by side should not share a writer unless it is safe for concurrent use.
+	Log io.Writer
+}
+
+// DefaultOutputOptions writes every output into the working directory
+func DefaultOutputOptions() OutputOptions {
+	return OutputOptions{
+		Directory:  ".",
+		Statistics: true,
+		GameDump:   true,
+		Log:        os.Stdout,
+	}
+}
+
+func (s *Server) SetOutputOptions(options OutputOptions) {
+	s.outputOptions = options
+}
+
+// logf writes a progress message to the log of the output options
+func (s *Server) logf(format string, args ...any) {
+	if s.outputOptions.Log != nil {
+		fmt.Fprintf(s.outputOptions.Log, format, args...)
+	}
+}
+
+func (s *Server) outputResults(gameStates [][]GameStateDump) error {
+	statistics := CalculateStatistics(gameStates)
+
+	statisticsJson, err := json.MarshalIndent(statistics.Average, "", "    ")
+	if err != nil {
+		return fmt.Errorf("encoding statistics: %w", err)
+	}
+	s.logf("Average Statistics:\n%s\n", statisticsJson)
+
+	if !s.outputOptions.Statistics && !s.outputOptions.GameDump {
+		return nil
+	}
+	if err := os.MkdirAll(s.outputOptions.Directory, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	if s.outputOptions.Statistics {
+		if err := writeStatistics(filepath.Join(s.outputOptions.Directory, StatisticsFileName), statistics); err != nil {
+			return err
+		}
+	}
+
+	if s.outputOptions.GameDump {
+		var flattenedGameStates []GameStateDump
+		for i := range gameStates {
+			flattenedGameStates = append(flattenedGameStates, gameStates[i]...)
+		}
+		if err := writeGameDump(filepath.Join(s.outputOptions.Directory, GameDumpFileName), flattenedGameStates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeStatistics(path string, statistics GameStatistics) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating statistics file: %w", err)
+	}
+	defer file.Close()
+	if err := statistics.ToSpreadsheet().Write(file); err != nil {
+		return fmt.Errorf("writing statistics file: %w", err)
+	}
+	return nil
+}
+
+func writeGameDump(path string, gameStates []GameStateDump) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating game dump file: %w", err)
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(gameStates); err != nil {
+		return fmt.Errorf("writing game dump file: %w", err)
+	}
+	return nil
+}
+
+// LoadGameDump reads a game dump written at the end of a run
+func LoadGameDump(path string) ([]GameStateDump, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening game dump: %w", err)
+	}
+	defer file.Close()
+	var gameStates []GameStateDump
+	if err := json.NewDecoder(file).Decode(&gameStates); err != nil {
+		return nil, fmt.Errorf("decoding game dump %s: %w", path, err)
+	}
+	return gameStates, nil
+}
@@ -2,11 +2,11 @@ package server
 
 import (
 	"SOMAS2023/internal/common/objects"
-	"SOMAS2023/internal/common/physics"
 	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/common/voting"
-	"fmt"
+	"math"
 	"slices"
+	"sort"
 
 	"github.com/google/uuid"
 )
@@ -22,29 +22,48 @@ func (s *Server) RunRoundLoop() {
 	// take care of agents that want to leave the bike and of the acceptance/ expulsion process
 	s.RunBikeSwitch(gameState)
 
+	// the riders may vote to change the governance and the constitution of their bike
+	s.RunReferendums()
+	s.RunAmendments()
+	s.RunImpeachments()
+
 	// get the direction decisions and pedalling forces
 	s.RunActionProcess()
 
-	// The Audi makes a decision
-	s.audi.UpdateGameState(gameState)
+	// The Audis make a decision
+	for _, audi := range s.audisInOrder() {
+		audi.UpdateGameState(gameState)
+	}
 
 	// Move the mega bikes
-	for _, bike := range s.megaBikes {
+	for _, bike := range s.megaBikesInOrder() {
 		// update mass dependent on number of agents on bike
 		bike.UpdateMass()
 		s.MovePhysicsObject(bike)
 	}
 
-	// Move the audi
-	s.MovePhysicsObject(s.audi)
+	// Move the audis
+	for _, audi := range s.audisInOrder() {
+		s.MovePhysicsObject(audi)
+	}
+
+	// Move the drifting lootboxes
+	if s.config.LootBoxDriftForce > 0 {
+		for _, lootBox := range s.lootBoxesInOrder() {
+			s.MovePhysicsObject(lootBox)
+		}
+	}
 
 	s.UpdateGameStates()
 
 	// Lootbox Distribution
 	s.LootboxCheckAndDistributions()
 
-	// Punish bikeless agents
-	s.punishBikelessAgents()
+	// The lootboxes left decay, and expire at the end of their lifetime
+	s.ageLootBoxes()
+
+	// Agents spend energy to stay alive, bikeless agents are punished
+	s.chargeRoundEnergy()
 
 	// Check if agents died
 	// Check Audi collision
@@ -53,28 +72,31 @@ func (s *Server) RunRoundLoop() {
 	s.UpdateGameStates()
 
 	// if the leader dies hold new elections
-	for _, bike := range s.GetMegaBikes() {
+	for _, bike := range s.megaBikesInOrder() {
 		gov := bike.GetGovernance()
 		agents := bike.GetAgents()
 		if len(agents) != 0 && (gov == utils.Leadership || gov == utils.Dictatorship) {
 			ruler := bike.GetRuler()
 			if _, ok := s.deadAgents[ruler]; ok {
-				agents := bike.GetAgents()
-				ruler := s.RulerElection(agents, gov)
-				bike.SetRuler(ruler)
+				s.electRuler(bike, gov, DeathElection, uuid.Nil)
 			}
 		}
 	}
+	s.reelectCouncils(DeathElection)
+
+	// rulers at the end of their term face a new election
+	s.endRulerTerms()
 
-	// Replenish objects
-	if utils.ReplenishLootBoxes {
+	// Replenish objects, lootboxes are not replenished during scarcity phases
+	if s.config.ReplenishLootBoxes && s.lootBoxPolicy.Replenishes(s.round) {
 		s.replenishLootBoxes()
 	}
-	if utils.ReplenishMegaBikes {
+	if s.config.ReplenishMegaBikes {
 		s.replenishMegaBikes()
 	}
 
 	s.RunMessagingSession()
+	s.round++
 }
 
 func (s *Server) RunBikeSwitch(gameState GameStateDump) {
@@ -89,6 +111,8 @@ func (s *Server) RunBikeSwitch(gameState GameStateDump) {
 	// update gamestate as it has changed
 	s.UpdateGameStates()
 	inLimbo = append(inLimbo, kickedOff...)
+	// sanction the riders, those at the end of the escalation are kicked out
+	inLimbo = append(inLimbo, s.RunSanctions()...)
 	// process the joining request
 	s.ProcessJoiningRequests(inLimbo)
 	// update gamestate as it has changed
@@ -97,7 +121,7 @@ func (s *Server) RunBikeSwitch(gameState GameStateDump) {
 
 func (s *Server) HandleKickoutProcess() []uuid.UUID {
 	allKicked := make([]uuid.UUID, 0)
-	for _, bike := range s.GetMegaBikes() {
+	for _, bike := range s.megaBikesInOrder() {
 		agents := bike.GetAgents()
 		if len(agents) != 0 {
 
@@ -106,12 +130,8 @@ func (s *Server) HandleKickoutProcess() []uuid.UUID {
 			// the kickout process only happens democratically in level 0 and level 1
 			switch bike.GetGovernance() {
 			case utils.Democracy:
-				// make map of weights of 1 for all agents on bike
-				agents := bike.GetAgents()
-				weights := make(map[uuid.UUID]float64)
-				for _, agent := range agents {
-					weights[agent.GetID()] = 1.0
-				}
+				// every rider has a weight of 1, unless a sanction reduced it
+				weights := s.riderWeights(bike.GetAgents())
 
 				// get which agents are getting kicked out
 				agentsVotes = bike.KickOutAgent(weights)
@@ -122,19 +142,23 @@ func (s *Server) HandleKickoutProcess() []uuid.UUID {
 				leader := s.GetAgentMap()[ruler]
 				weights := leader.DecideWeights(utils.Kickout)
 				// get which agents are getting kicked out
-				agentsVotes = bike.KickOutAgent(weights)
+				agentsVotes = bike.KickOutAgent(s.sanctionedWeights(weights))
 
 			case utils.Dictatorship:
 				// in level 2 only the ruler can kick out people
 				dictator := s.GetAgentMap()[bike.GetRuler()]
 				agentsVotes = dictator.DecideKickOut()
+
+			case utils.Council:
+				// only the council members vote on kickouts
+				agentsVotes = bike.CouncilKickOut(s.riderWeights(s.councilMembers(bike)))
 			}
 
 			// perform kickout
 			leaderKickedOut := false
 			allKicked = append(allKicked, agentsVotes...)
 			for _, agentID := range agentsVotes {
-				fmt.Printf("kicking out agent %s\n", agentID)
+				s.logf("kicking out agent %s\n", agentID)
 				s.RemoveAgentFromBike(s.GetAgentMap()[agentID])
 				// if the leader was kicked out vote for a new one
 				if agentID == bike.GetRuler() {
@@ -143,21 +167,21 @@ func (s *Server) HandleKickoutProcess() []uuid.UUID {
 			}
 			s.UpdateGameStates()
 			if leaderKickedOut && len(bike.GetAgents()) != 0 && bike.GetGovernance() == utils.Leadership {
-				ruler := s.RulerElection(bike.GetAgents(), utils.Leadership)
-				bike.SetRuler(ruler)
-
+				s.electRuler(bike, utils.Leadership, KickoutElection, uuid.Nil)
 			}
 		}
 
 	}
+	s.reelectCouncils(KickoutElection)
 	return allKicked
 }
 
 func (s *Server) GetLeavingDecisions(gameState objects.IGameState) []uuid.UUID {
 	leavingAgents := make([]uuid.UUID, 0)
-	for agentId, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
+		agentId := agent.GetID()
 		if agent.GetBikeStatus() {
-			agent.UpdateGameState(gameState)
+			agent.UpdateGameState(s.observedBy(gameState, agent))
 			agent.UpdateAgentInternalState()
 			switch agent.DecideAction() {
 			case objects.Pedal:
@@ -172,19 +196,19 @@ func (s *Server) GetLeavingDecisions(gameState objects.IGameState) []uuid.UUID {
 				// will only be finalised then
 				leavingAgents = append(leavingAgents, agentId)
 				s.RemoveAgentFromBike(agent)
-				fmt.Printf("Agent %s left the bike \n", agentId)
+				s.logf("Agent %s left the bike \n", agentId)
 			default:
 				panic("agent decided invalid action")
 			}
 		}
 	}
 	s.UpdateGameStates()
-	for _, bike := range s.GetMegaBikes() {
+	for _, bike := range s.megaBikesInOrder() {
 		if slices.Contains(leavingAgents, bike.GetRuler()) && len(bike.GetAgents()) != 0 {
-			ruler := s.RulerElection(bike.GetAgents(), utils.Leadership)
-			bike.SetRuler(ruler)
+			s.electRuler(bike, bike.GetGovernance(), LeavingElection, uuid.Nil)
 		}
 	}
+	s.reelectCouncils(LeavingElection)
 	return leavingAgents
 }
 
@@ -194,11 +218,12 @@ func (s *Server) ProcessJoiningRequests(inLimbo []uuid.UUID) {
 	// 1. group agents that have onBike = false by the bike they are trying to join
 	bikeRequests := s.GetJoiningRequests(inLimbo)
 	// 2. pass to agents on each of the desired bikes a list of all agents trying to join
-	for bikeID, pendingAgents := range bikeRequests {
+	for _, bikeID := range utils.SortedIDs(bikeRequests) {
+		pendingAgents := bikeRequests[bikeID]
 		agents := s.megaBikes[bikeID].GetAgents()
 		if len(agents) == 0 {
 			for i, pendingAgent := range pendingAgents {
-				if i <= utils.BikersOnBike {
+				if i <= s.config.BikersOnBike {
 					acceptedAgent := s.GetAgentMap()[pendingAgent]
 					s.AddAgentToBike(acceptedAgent)
 				} else {
@@ -210,9 +235,9 @@ func (s *Server) ProcessJoiningRequests(inLimbo []uuid.UUID) {
 			gov := s.megaBikes[bikeID].GetGovernance()
 			if gov == utils.Dictatorship || gov == utils.Leadership {
 				// run election process
-				agents := s.megaBikes[bikeID].GetAgents()
-				ruler := s.RulerElection(agents, gov)
-				s.megaBikes[bikeID].SetRuler(ruler)
+				s.electRuler(s.megaBikes[bikeID], gov, BoardingElection, uuid.Nil)
+			} else if gov == utils.Council {
+				s.electCouncil(s.megaBikes[bikeID], BoardingElection, nil)
 			}
 		} else {
 			bike := s.GetMegaBikes()[bikeID]
@@ -233,7 +258,7 @@ func (s *Server) ProcessJoiningRequests(inLimbo []uuid.UUID) {
 				}
 
 				// accept agents based on the response outcome (it will have to be a ranking system, as only 8-n bikers can be accepted)
-				acceptedRanked = voting.GetAcceptanceRanking(responses, weights)
+				acceptedRanked = voting.GetAcceptanceRanking(responses, s.sanctionedWeights(weights), bike.GetConstitution().AdmissionQuorum)
 			case utils.Leadership:
 				// get the map of weights from the leader
 				leader := s.GetAgentMap()[bike.GetRuler()]
@@ -246,20 +271,32 @@ func (s *Server) ProcessJoiningRequests(inLimbo []uuid.UUID) {
 				}
 
 				// accept agents based on the response outcome (it will have to be a ranking system, as only 8-n bikers can be accepted)
-				acceptedRanked = voting.GetAcceptanceRanking(responses, weights)
+				acceptedRanked = voting.GetAcceptanceRanking(responses, s.sanctionedWeights(weights), bike.GetConstitution().AdmissionQuorum)
 			case utils.Dictatorship:
 				dictator := s.GetAgentMap()[bike.GetRuler()]
 				acceptedRankedMap := dictator.DecideJoining(pendingAgents)
-				for agentID, accepted := range acceptedRankedMap {
+				for _, agentID := range utils.SortedIDs(acceptedRankedMap) {
+					accepted := acceptedRankedMap[agentID]
 					if accepted {
 						acceptedRanked = append(acceptedRanked, agentID)
 					}
 				}
+			case utils.Council:
+				// only the council members vote on who joins, with equal weights
+				weights := make(map[uuid.UUID]float64)
+				responses := make(map[uuid.UUID](map[uuid.UUID]bool))
+				for _, agent := range agents {
+					if slices.Contains(bike.GetCouncil(), agent.GetID()) {
+						weights[agent.GetID()] = 1.0
+						responses[agent.GetID()] = agent.DecideJoining(pendingAgents)
+					}
+				}
+				acceptedRanked = voting.GetAcceptanceRanking(responses, s.sanctionedWeights(weights), bike.GetConstitution().AdmissionQuorum)
 			}
 
 			// run acceptance process
 			totalSeatsFilled := len(agents)
-			emptySpaces := utils.BikersOnBike - totalSeatsFilled
+			emptySpaces := s.config.BikersOnBike - totalSeatsFilled
 
 			for i := 0; i < min(emptySpaces, len(acceptedRanked)); i++ {
 				accepted := acceptedRanked[i]
@@ -268,11 +305,12 @@ func (s *Server) ProcessJoiningRequests(inLimbo []uuid.UUID) {
 			}
 		}
 	}
+	s.reelectCouncils(BoardingElection)
 }
 
 func (s *Server) RunActionProcess() {
 
-	for _, bike := range s.GetMegaBikes() {
+	for _, bike := range s.megaBikesInOrder() {
 		agents := bike.GetAgents()
 		if len(agents) == 0 {
 			continue
@@ -290,26 +328,36 @@ func (s *Server) RunActionProcess() {
 				weights[agent.GetID()] = 1.0
 			}
 			direction = s.RunDemocraticAction(bike, weights)
-			for _, agent := range agents {
-				agent.UpdateEnergyLevel(-utils.DeliberativeDemocracyPenalty)
-			}
 		case utils.Leadership:
 			// get weights from leader
 			leader := s.GetAgentMap()[bike.GetRuler()]
 			weights := leader.DecideWeights(utils.Direction)
 			direction = s.RunDemocraticAction(bike, weights)
-			for _, agent := range agents {
-				agent.UpdateEnergyLevel(-utils.LeadershipDemocracyPenalty)
-			}
 		case utils.Dictatorship:
 			direction = s.RunRulerAction(bike)
+		case utils.Council:
+			// every rider proposes a direction but only the council members vote
+			direction = s.RunDemocraticAction(bike, councilWeights(bike))
+		}
+		for _, agent := range agents {
+			agent.UpdateEnergyLevel(-s.energyModel.GovernanceCost(electedGovernance))
 		}
 
 		for _, agent := range agents {
 			agent.DecideForce(direction)
-			// deplete energy
-			energyLost := agent.GetForces().Pedal * utils.MovingDepletion
-			agent.UpdateEnergyLevel(-energyLost)
+		}
+		// deplete energy once every rider has decided, as the cost of riding may depend on the other riders
+		for _, agent := range agents {
+			agent.UpdateEnergyLevel(-s.energyModel.RidingCost(agent.GetForces(), bike))
+		}
+
+		// riders may pool energy into a shield protecting the bike from the audi
+		if s.config.AudiShieldCost > 0 {
+			for _, agent := range agents {
+				contribution := math.Max(0, math.Min(agent.DecideShieldContribution(), agent.GetEnergyLevel()))
+				agent.UpdateEnergyLevel(-contribution)
+				bike.AddShield(contribution)
+			}
 		}
 	}
 }
@@ -325,13 +373,21 @@ func (s *Server) MovePhysicsObject(po objects.IPhysicsObject) {
 	initialState := po.GetPhysicalState()
 
 	// Generates a new state based on the force and orientation
-	finalState := physics.GenerateNewState(initialState, force, orientation)
+	finalState := s.physicsEngine.GenerateNewState(initialState, force, orientation)
+	// Keeps the object on the grid according to the boundary mode
+	finalState, orientation = s.physicsEngine.Boundary.Apply(finalState, orientation)
 
 	// Sets the new physical state (i.e. updates gamestate)
 	po.SetPhysicalState(finalState)
+	po.SetOrientation(orientation)
 }
 
+// GetWinningDirection counts the votes with the voting method of the simulation config, bikes use the one of their constitution
 func (s *Server) GetWinningDirection(finalVotes map[uuid.UUID]voting.LootboxVoteMap, weights map[uuid.UUID]float64) uuid.UUID {
+	return s.winningDirection(finalVotes, weights, s.config.VoteAction)
+}
+
+func (s *Server) winningDirection(finalVotes map[uuid.UUID]voting.LootboxVoteMap, weights map[uuid.UUID]float64, method utils.VoteMethod) uuid.UUID {
 	// get overall winner direction using chosen voting strategy
 
 	// this allows to get a slice of the interface from that of the specific type
@@ -343,47 +399,153 @@ func (s *Server) GetWinningDirection(finalVotes map[uuid.UUID]voting.LootboxVote
 	}
 
 	// TODO integrate voting functions from group 8
-	return voting.WinnerFromDist(IfinalVotes, weights)
+	return voting.WinnerFromDist(IfinalVotes, weights, method)
 }
 
 func (s *Server) AudiCollisionCheck() {
-	// Check collision for audi with any megaBike
-	for bikeid, megabike := range s.GetMegaBikes() {
-		if s.audi.CheckForCollision(megabike) {
+	for _, audi := range s.audisInOrder() {
+		// an audi cooling down after a hit does not harm anyone
+		if audi.GetCooldown() > 0 {
+			audi.SetCooldown(audi.GetCooldown() - 1)
+			continue
+		}
+		// Check collision for audi with any megaBike, in the order the audi ran into them
+		for _, impact := range impactsInOrder(audi, s.megaBikesInOrder()) {
+			megabike := impact.object.(objects.IMegaBike)
+			bikeid := megabike.GetID()
 			// Collision detected
-			fmt.Printf("Collision detected between Audi and MegaBike %s \n", bikeid)
-			for _, agentToDelete := range megabike.GetAgents() {
-				fmt.Printf("Agent %s killed by Audi \n", agentToDelete.GetID())
-				s.RemoveAgent(agentToDelete)
+			s.logf("Collision detected between Audi %s and MegaBike %s \n", audi.GetID(), bikeid)
+			if s.config.AudiShieldCost > 0 && megabike.GetShield() >= s.config.AudiShieldCost {
+				s.logf("Shield of MegaBike %s blocked the Audi \n", bikeid)
+				megabike.AddShield(-s.config.AudiShieldCost)
+				continue
 			}
-			if utils.AudiRemovesMegaBike {
-				fmt.Printf("Megabike %s removed by Audi \n", megabike.GetID())
-				delete(s.megaBikes, megabike.GetID())
+			s.audiHit(audi, megabike)
+			if s.config.AudiCooldown > 0 || s.config.AudiRespawns {
+				// the audi backs off and leaves the other bikes in its path alone
+				s.audiBackOff(audi)
+				break
 			}
 		}
 	}
 }
 
-func (s *Server) LootboxCheckAndDistributions() {
+// audiHit applies the outcome of the audi hitting a megabike to its riders, and removes the bike if configured
+func (s *Server) audiHit(audi objects.IAudi, megabike objects.IMegaBike) {
+	switch s.config.AudiCollision {
+	case utils.KillRandomRiders:
+		for _, agent := range megabike.GetAgents() {
+			if s.rng.Float64() < s.config.AudiKillProbability {
+				s.logf("Agent %s killed by Audi \n", agent.GetID())
+				s.RemoveAgent(agent)
+			}
+		}
+	case utils.DamageRiders:
+		// the faster the audi compared to the bike, the harder the hit
+		impactSpeed := math.Max(0, audi.GetVelocity()-megabike.GetVelocity())
+		for _, agent := range megabike.GetAgents() {
+			s.logf("Agent %s hurt by Audi \n", agent.GetID())
+			agent.UpdateEnergyLevel(-s.config.AudiDamage * impactSpeed)
+		}
+	default:
+		for _, agentToDelete := range megabike.GetAgents() {
+			s.logf("Agent %s killed by Audi \n", agentToDelete.GetID())
+			s.RemoveAgent(agentToDelete)
+		}
+	}
+	if s.config.AudiRemovesMegaBike {
+		// the riders that survived the hit are thrown off the bike
+		for _, agent := range megabike.GetAgents() {
+			s.RemoveAgentFromBike(agent)
+		}
+		s.logf("Megabike %s removed by Audi \n", megabike.GetID())
+		delete(s.megaBikes, megabike.GetID())
+	}
+}
 
-	// checks how many bikes have looted one lootbox to split it between them
-	looted := make(map[uuid.UUID]int)
-	for _, megabike := range s.GetMegaBikes() {
-		for lootid, lootbox := range s.GetLootBoxes() {
-			if megabike.CheckForCollision(lootbox) { // && len(megabike.GetAgents()) != 0
-				if value, ok := looted[lootid]; ok {
-					looted[lootid] = value + 1
-				} else {
-					looted[lootid] = 1
-				}
+// audiBackOff stops the audi after a hit, sending it to a random position if it respawns
+func (s *Server) audiBackOff(audi objects.IAudi) {
+	audi.SetCooldown(s.config.AudiCooldown)
+	state := audi.GetPhysicalState()
+	state.Velocity = 0.0
+	state.Acceleration = 0.0
+	if s.config.AudiRespawns {
+		state.Position = utils.GenerateRandomCoordinates(s.rng, s.config.GridWidth, s.config.GridHeight)
+		s.logf("Audi %s respawned \n", audi.GetID())
+	}
+	audi.SetPhysicalState(state)
+}
+
+// impact is a collision with an object during the last step, time is the fraction of the step at which it happened
+type impact struct {
+	object objects.IPhysicsObject
+	time   float64
+}
+
+// impactsInOrder returns the objects that collided with mover during the last step, sorted by time of impact
+func impactsInOrder[T objects.IPhysicsObject](mover objects.IPhysicsObject, others []T) []impact {
+	impacts := make([]impact, 0)
+	for _, other := range others {
+		if time, collided := mover.TimeOfImpact(other); collided {
+			impacts = append(impacts, impact{object: other, time: time})
+		}
+	}
+	sort.SliceStable(impacts, func(i, j int) bool {
+		return impacts[i].time < impacts[j].time
+	})
+	return impacts
+}
+
+// lootboxContestants returns, for every lootbox that has been reached, the bikes that reached it during the
+// last step sorted by time of impact (then by ID)
+func (s *Server) lootboxContestants() map[uuid.UUID][]objects.LootContestant {
+	contestants := make(map[uuid.UUID][]objects.LootContestant)
+	lootBoxIndex := newSpatialIndex(s.lootBoxes, s.physicsEngine.Boundary, s.config.CollisionThreshold)
+	// drifting lootboxes may have crossed the path of a bike from further away
+	maxDrift := 0.0
+	for _, lootBox := range s.lootBoxesInOrder() {
+		maxDrift = math.Max(maxDrift, math.Sqrt(s.physicsEngine.Boundary.ComputeDistance(lootBox.GetPreviousPosition(), lootBox.GetPosition())))
+	}
+	for _, megabike := range s.megaBikesInOrder() {
+		// only the lootboxes near the path of the bike can have been reached
+		start := megabike.GetPreviousPosition()
+		xMoved, yMoved := s.physicsEngine.Boundary.Displacement(start, megabike.GetPosition())
+		middle := utils.Coordinates{X: start.X + xMoved/2, Y: start.Y + yMoved/2}
+		reach := math.Hypot(xMoved, yMoved)/2 + maxDrift + s.config.CollisionThreshold + utils.Epsilon
+		for _, lootid := range lootBoxIndex.Within(middle, reach) {
+			if time, collided := megabike.TimeOfImpact(s.lootBoxes[lootid]); collided { // && len(megabike.GetAgents()) != 0
+				contestants[lootid] = append(contestants[lootid], objects.LootContestant{Bike: megabike, TimeOfImpact: time})
 			}
 		}
 	}
-	for bikeid, megabike := range s.GetMegaBikes() {
-		for lootid, lootbox := range s.GetLootBoxes() {
-			if megabike.CheckForCollision(lootbox) {
+	// the bikes were visited in order of ID, keep it amongst bikes arriving at the same time
+	for _, lootid := range utils.SortedIDs(contestants) {
+		sort.SliceStable(contestants[lootid], func(i, j int) bool {
+			return contestants[lootid][i].TimeOfImpact < contestants[lootid][j].TimeOfImpact
+		})
+	}
+	return contestants
+}
+
+func (s *Server) LootboxCheckAndDistributions() {
+
+	// finds the bikes that reached each lootbox, the sharing policy decides how they share it
+	contestants := s.lootboxContestants()
+	shares := make(map[uuid.UUID]map[uuid.UUID]float64, len(contestants))
+	s.lootShares = make([]LootShareDump, 0, len(contestants))
+	lootIDs := utils.SortedIDs(contestants)
+	for _, lootid := range lootIDs {
+		shares[lootid] = s.lootSharing.Share(s.lootBoxes[lootid], contestants[lootid])
+		s.lootShares = append(s.lootShares, newLootShareDump(lootid, contestants[lootid], shares[lootid]))
+	}
+	for _, megabike := range s.megaBikesInOrder() {
+		bikeid := megabike.GetID()
+		// only the lootboxes reached during the last step can be shared
+		for _, lootid := range lootIDs {
+			lootbox := s.lootBoxes[lootid]
+			if share := shares[lootid][bikeid]; share > 0 {
 				// Collision detected
-				fmt.Printf("Collision detected between MegaBike %s and LootBox %s \n", bikeid, lootid)
+				s.logf("Collision detected between MegaBike %s and LootBox %s \n", bikeid, lootid)
 				agents := megabike.GetAgents()
 				totAgents := len(agents)
 
@@ -409,7 +571,7 @@ func (s *Server) LootboxCheckAndDistributions() {
 						for _, agent := range agents {
 							weights[agent.GetID()] = 1.0
 						}
-						winningAllocation = voting.CumulativeDist(Iallocations, weights)
+						winningAllocation = voting.AllocationFromDist(Iallocations, s.sanctionedWeights(weights), megabike.GetConstitution().LootAllocation)
 					case utils.Leadership:
 						// get the map of weights from the leader
 						leader := s.GetAgentMap()[megabike.GetRuler()]
@@ -432,25 +594,42 @@ func (s *Server) LootboxCheckAndDistributions() {
 						for i, v := range allAllocations {
 							Iallocations[i] = v
 						}
-						winningAllocation = voting.CumulativeDist(Iallocations, weights)
+						winningAllocation = voting.AllocationFromDist(Iallocations, s.sanctionedWeights(weights), megabike.GetConstitution().LootAllocation)
 					case utils.Dictatorship:
 						// dictator decides the allocation
 						leader := s.GetAgentMap()[megabike.GetRuler()]
 						winningAllocation = leader.DecideDictatorAllocation()
+					case utils.Council:
+						// the council members vote on the allocation with equal weights
+						allocations := make(map[uuid.UUID]voting.IVoter)
+						weights := make(map[uuid.UUID]float64)
+						for _, agent := range agents {
+							if slices.Contains(megabike.GetCouncil(), agent.GetID()) {
+								allocations[agent.GetID()] = agent.DecideAllocation()
+								weights[agent.GetID()] = 1.0
+							}
+						}
+						winningAllocation = voting.AllocationFromDist(allocations, s.sanctionedWeights(weights), megabike.GetConstitution().LootAllocation)
 					}
 
-					bikeShare := float64(looted[lootid]) // how many other bikes have looted this box
-
-					for agentID, allocation := range winningAllocation {
-						fmt.Printf("total loot: %f \n", lootbox.GetTotalResources())
-						lootShare := allocation * (lootbox.GetTotalResources() / bikeShare)
+					// the fine pool is shared out with the loot, and the riders excluded from the loot forfeit
+					// their share to the fine pool
+					var excludedShare float64
+					winningAllocation, excludedShare = s.excludeFromLoot(winningAllocation)
+					loot := lootbox.GetTotalResources()*share + megabike.GetFinePool()
+					megabike.AddFinePool(excludedShare*loot - megabike.GetFinePool())
+
+					for _, agentID := range utils.SortedIDs(winningAllocation) {
+						allocation := winningAllocation[agentID]
+						s.logf("total loot: %f \n", lootbox.GetTotalResources())
+						lootShare := allocation * loot
 						agent := s.GetAgentMap()[agentID]
 						// Allocate loot based on the calculated utility share
-						fmt.Printf("Agent %s allocated %f loot \n", agent.GetID(), lootShare)
+						s.logf("Agent %s allocated %f loot \n", agent.GetID(), lootShare)
 						agent.UpdateEnergyLevel(lootShare)
 						// Allocate points if the box is of the right colour
 						if agent.GetColour() == lootbox.GetColour() {
-							agent.UpdatePoints(utils.PointsFromSameColouredLootBox)
+							agent.UpdatePoints(s.config.PointsFromSameColouredLootBox)
 						}
 					}
 				}
@@ -459,35 +638,50 @@ func (s *Server) LootboxCheckAndDistributions() {
 	}
 
 	// despawn lootboxes that have been looted
-	for id, loot := range looted {
-		if loot > 0 {
-			delete(s.lootBoxes, id)
-		}
+	for id := range contestants {
+		delete(s.lootBoxes, id)
 	}
 }
 
 func (s *Server) SetDestinationBikes() {
-	for _, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
 		if !agent.GetBikeStatus() {
 			agent.SetBike(agent.ChangeBike())
 		}
 	}
 }
 
+// ageLootBoxes ages every lootbox and removes the ones that have expired
+func (s *Server) ageLootBoxes() {
+	for _, lootBox := range s.lootBoxesInOrder() {
+		lootBox.Age()
+		if lootBox.GetTimeToLive() == 0 {
+			s.logf("Lootbox %s expired \n", lootBox.GetID())
+			delete(s.lootBoxes, lootBox.GetID())
+		}
+	}
+}
+
 func (s *Server) unaliveAgents() {
-	for id, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
+		id := agent.GetID()
 		if agent.GetEnergyLevel() < 0 {
-			fmt.Printf("Agent %s got game ended\n", id)
+			s.logf("Agent %s got game ended\n", id)
 			s.RemoveAgent(agent)
 		}
 	}
 }
 
-func (s *Server) punishBikelessAgents() {
-	for id, agent := range s.GetAgentMap() {
+// chargeRoundEnergy takes the energy every agent spends to stay alive for a round, and the penalty for
+// being off a bike from bikeless agents
+func (s *Server) chargeRoundEnergy() {
+	for _, agent := range s.agentsInOrder() {
+		id := agent.GetID()
+		cost := s.energyModel.BasalCost()
 		if _, ok := s.megaBikeRiders[id]; !ok {
 			// Agent is not on a bike
-			agent.UpdateEnergyLevel(utils.LimboEnergyPenalty)
+			cost += s.energyModel.LimboCost()
 		}
+		agent.UpdateEnergyLevel(-cost)
 	}
 }
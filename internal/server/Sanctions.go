@@ -0,0 +1,169 @@
+package server
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// RunSanctions lets every bike sanction its riders: the riders of a democracy vote on sanctions, the members of a
+// council vote amongst themselves, and a leader or dictator imposes them. Sanctions escalate with the offences of
+// the agent, from a warning up to a kickout, and the agents kicked out are returned.
+func (s *Server) RunSanctions() []uuid.UUID {
+	s.sanctions = make([]SanctionDump, 0)
+	kicked := make([]uuid.UUID, 0)
+	for _, bike := range s.megaBikesInOrder() {
+		if len(bike.GetAgents()) == 0 {
+			continue
+		}
+		for _, agentID := range s.sanctionedRiders(bike) {
+			// rulers cannot be sanctioned, only impeached
+			if s.megaBikeRiders[agentID] != bike.GetID() || agentID == bike.GetRuler() {
+				continue
+			}
+			agent := s.GetAgentMap()[agentID]
+			if s.sanction(bike, agent) == utils.KickoutSanction {
+				s.logf("agent %s is kicked out as a sanction\n", agentID)
+				s.RemoveAgentFromBike(agent)
+				kicked = append(kicked, agentID)
+			}
+		}
+	}
+	if len(s.sanctions) != 0 {
+		s.UpdateGameStates()
+		s.reelectCouncils(KickoutElection)
+	}
+	return kicked
+}
+
+// GetSanctionHistory returns the sanctions imposed on an agent during the current game, oldest first
+func (s *Server) GetSanctionHistory(agentID uuid.UUID) []SanctionDump {
+	return slices.Clone(s.sanctionHistory[agentID])
+}
+
+// sanctionedRiders returns the riders a bike decides to sanction, a rider is sanctioned by vote with more than
+// the kickout threshold of the constitution of the total weight of the voters
+func (s *Server) sanctionedRiders(bike objects.IMegaBike) []uuid.UUID {
+	voters := make([]objects.IBaseBiker, 0)
+	switch bike.GetGovernance() {
+	case utils.Democracy:
+		voters = bike.GetAgents()
+	case utils.Council:
+		voters = s.councilMembers(bike)
+	case utils.Leadership, utils.Dictatorship:
+		ruler, ok := s.GetAgentMap()[bike.GetRuler()]
+		if !ok {
+			return []uuid.UUID{}
+		}
+		return ruler.DecideSanctions()
+	}
+
+	weights := s.riderWeights(voters)
+	voteCount := make(map[uuid.UUID]float64)
+	totalWeight := 0.0
+	for _, voter := range voters {
+		totalWeight += weights[voter.GetID()]
+		for agentID, votes := range voter.VoteForSanction() {
+			voteCount[agentID] += weights[voter.GetID()] * float64(votes)
+		}
+	}
+	sanctioned := make([]uuid.UUID, 0)
+	for _, agentID := range utils.SortedIDs(voteCount) {
+		if voteCount[agentID] > totalWeight*bike.GetConstitution().KickoutThreshold {
+			sanctioned = append(sanctioned, agentID)
+		}
+	}
+	return sanctioned
+}
+
+// sanction imposes on a rider the sanction its offences call for, and returns it
+func (s *Server) sanction(bike objects.IMegaBike, agent objects.IBaseBiker) utils.SanctionType {
+	offence := s.offences(agent.GetID()) + 1
+	sanction := s.config.SanctionOf(offence)
+	if sanction == utils.FineSanction {
+		fine := min(s.config.SanctionFine, agent.GetEnergyLevel())
+		agent.UpdateEnergyLevel(-fine)
+		bike.AddFinePool(fine)
+	}
+	s.logf("agent %s gets a %s for offence %d\n", agent.GetID(), sanction, offence)
+	record := SanctionDump{
+		Round:    s.round,
+		Bike:     bike.GetID(),
+		Agent:    agent.GetID(),
+		Sanction: sanction,
+		Offence:  offence,
+	}
+	s.sanctions = append(s.sanctions, record)
+	s.sanctionHistory[agent.GetID()] = append(s.sanctionHistory[agent.GetID()], record)
+	return sanction
+}
+
+// offences returns the number of sanctions of an agent that have not been forgotten yet
+func (s *Server) offences(agentID uuid.UUID) int {
+	offences := 0
+	for _, record := range s.sanctionHistory[agentID] {
+		if s.config.SanctionAmnesty == 0 || s.round-record.Round < s.config.SanctionAmnesty {
+			offences++
+		}
+	}
+	return offences
+}
+
+// underSanction reports whether a sanction lasting a while is still running against an agent
+func (s *Server) underSanction(agentID uuid.UUID, sanction utils.SanctionType) bool {
+	for _, record := range s.sanctionHistory[agentID] {
+		if record.Sanction == sanction && s.round-record.Round < s.config.SanctionDuration {
+			return true
+		}
+	}
+	return false
+}
+
+// sanctionedWeights reduces the vote weights of the agents whose vote weight is reduced by a sanction, the
+// weights are left untouched if there are none
+func (s *Server) sanctionedWeights(weights map[uuid.UUID]float64) map[uuid.UUID]float64 {
+	if len(s.sanctionHistory) == 0 {
+		return weights
+	}
+	reduced := weights
+	cloned := false
+	for _, agentID := range utils.SortedIDs(weights) {
+		if s.underSanction(agentID, utils.VoteWeightSanction) {
+			if !cloned {
+				reduced = maps.Clone(weights)
+				cloned = true
+			}
+			reduced[agentID] *= s.config.SanctionVoteWeight
+		}
+	}
+	return reduced
+}
+
+// riderWeights gives the votes of every rider a weight of 1, unless a sanction reduced it
+func (s *Server) riderWeights(riders []objects.IBaseBiker) map[uuid.UUID]float64 {
+	weights := make(map[uuid.UUID]float64, len(riders))
+	for _, rider := range riders {
+		weights[rider.GetID()] = 1.0
+	}
+	return s.sanctionedWeights(weights)
+}
+
+// excludeFromLoot takes the agents excluded from the loot out of an allocation, their shares are returned too
+func (s *Server) excludeFromLoot(allocation map[uuid.UUID]float64) (map[uuid.UUID]float64, float64) {
+	if len(s.sanctionHistory) == 0 {
+		return allocation, 0.0
+	}
+	excludedShare := 0.0
+	included := make(map[uuid.UUID]float64, len(allocation))
+	for _, agentID := range utils.SortedIDs(allocation) {
+		if s.underSanction(agentID, utils.LootExclusionSanction) {
+			excludedShare += allocation[agentID]
+		} else {
+			included[agentID] = allocation[agentID]
+		}
+	}
+	return included, excludedShare
+}
@@ -2,25 +2,23 @@ package server
 
 import (
 	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/physics"
 	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/common/voting"
-	"encoding/json"
 	"fmt"
-	"os"
+	"math/rand"
+	"time"
 
 	baseserver "github.com/MattSScott/basePlatformSOMAS/BaseServer"
 	"github.com/google/uuid"
 )
 
-const LootBoxCount = MegaBikeCount * 3    // 3 available lootboxes per megabike
-const MegaBikeCount = BikerAgentCount / 4 // Megabikes should have an average of 4 riders
-const BikerAgentCount = 20
-
 type IBaseBikerServer interface {
 	baseserver.IServer[objects.IBaseBiker]
 	GetMegaBikes() map[uuid.UUID]objects.IMegaBike
 	GetLootBoxes() map[uuid.UUID]objects.ILootBox
 	GetAudi() objects.IAudi
+	GetAudis() map[uuid.UUID]objects.IAudi
 	GetJoiningRequests([]uuid.UUID) map[uuid.UUID][]uuid.UUID
 	GetRandomBikeId() uuid.UUID
 	RulerElection(agents []objects.IBaseBiker, governance utils.Governance) uuid.UUID
@@ -36,9 +34,23 @@ type IBaseBikerServer interface {
 	FoundingInstitutions()
 	GetWinningDirection(finalVotes map[uuid.UUID]voting.LootboxVoteMap, weights map[uuid.UUID]float64) uuid.UUID
 	LootboxCheckAndDistributions()
+	RunReferendums()
+	RunAmendments()
+	RunImpeachments()
+	GetElectionHistory(bikeID uuid.UUID) []ElectionDump
+	RunSanctions() []uuid.UUID
+	GetSanctionHistory(agentID uuid.UUID) []SanctionDump
 	ResetGameState()
 	GetDeadAgents() map[uuid.UUID]objects.IBaseBiker
 	UpdateGameStates()
+	GetConfig() utils.SimConfig
+	SetOutputOptions(options OutputOptions)
+	SetPhysicsModel(model physics.IPhysicsModel)
+	SetEnergyModel(model objects.IEnergyModel)
+	SetLootBoxSpawnPolicy(policy objects.ILootBoxSpawnPolicy)
+	SetLootSharingPolicy(policy objects.ILootSharingPolicy)
+	Run() error
+	PlayGames() [][]GameStateDump
 }
 
 type Server struct {
@@ -48,24 +60,98 @@ type Server struct {
 	// megaBikeRiders is a mapping from Agent ID -> ID of the bike that they are riding
 	// helps with efficiently managing ridership status
 	megaBikeRiders  map[uuid.UUID]uuid.UUID
-	audi            objects.IAudi
+	audis           map[uuid.UUID]objects.IAudi
 	deadAgents      map[uuid.UUID]objects.IBaseBiker
 	foundingChoices map[uuid.UUID]utils.Governance
+	config          utils.SimConfig
+	physicsEngine   physics.Engine
+	energyModel     objects.IEnergyModel
+	lootBoxPolicy   objects.ILootBoxSpawnPolicy
+	lootSharing     objects.ILootSharingPolicy
+	// how the lootboxes looted in the last round were shared
+	lootShares []LootShareDump
+	// noise of the lootboxes seen from every bike, see observedBy
+	sightings map[sightingKey]sighting
+	// elections held on every bike during the current game
+	elections map[uuid.UUID][]ElectionDump
+	// sanctions imposed on every agent during the current game, and those imposed in the last round
+	sanctionHistory map[uuid.UUID][]SanctionDump
+	sanctions       []SanctionDump
+	outputOptions   OutputOptions
+	// round of the current game, the lootbox policy may only replenish lootboxes in some rounds
+	round int
+	// rng is the only source of randomness of the simulation, it is seeded from config.Seed
+	rng *rand.Rand
 }
 
+// Initialize creates a server using the default simulation parameters
 func Initialize(iterations int) IBaseBikerServer {
+	server, err := InitializeWithConfig(iterations, utils.DefaultSimConfig())
+	if err != nil {
+		panic(err)
+	}
+	return server
+}
+
+// InitializeWithConfig creates a server using the given simulation parameters, which are
+// expected to have been validated. If config.Seed is 0 a seed is picked from the clock and
+// can be read back through GetConfig.
+func InitializeWithConfig(iterations int, config utils.SimConfig) (IBaseBikerServer, error) {
+	return newServer(iterations, config, DefaultAgentRegistry())
+}
+
+// InitializeWithRegistry creates a server that spawns its agents from the given teams
+// instead of the default registry
+func InitializeWithRegistry(iterations int, config utils.SimConfig, registry AgentRegistry) (IBaseBikerServer, error) {
+	return newServer(iterations, config, registry)
+}
+
+// newServer creates a server that shares no state with any other server, so several of them
+// can be run on separate goroutines
+func newServer(iterations int, config utils.SimConfig, registry AgentRegistry) (*Server, error) {
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	rng := utils.NewRand(config.Seed)
+	agentGenerators, err := registry.GetAgentGenerators(config, rng)
+	if err != nil {
+		return nil, fmt.Errorf("spawning agents: %w", err)
+	}
+
 	server := &Server{
-		BaseServer:     *baseserver.CreateServer[objects.IBaseBiker](GetAgentGenerators(), iterations),
-		lootBoxes:      make(map[uuid.UUID]objects.ILootBox),
-		megaBikes:      make(map[uuid.UUID]objects.IMegaBike),
-		megaBikeRiders: make(map[uuid.UUID]uuid.UUID),
-		deadAgents:     make(map[uuid.UUID]objects.IBaseBiker),
-		audi:           objects.GetIAudi(),
+		BaseServer:      *baseserver.CreateServer[objects.IBaseBiker](agentGenerators, iterations),
+		lootBoxes:       make(map[uuid.UUID]objects.ILootBox),
+		megaBikes:       make(map[uuid.UUID]objects.IMegaBike),
+		megaBikeRiders:  make(map[uuid.UUID]uuid.UUID),
+		deadAgents:      make(map[uuid.UUID]objects.IBaseBiker),
+		audis:           spawnAudis(config, rng),
+		config:          config,
+		physicsEngine:   physics.NewEngine(config),
+		energyModel:     objects.GetEnergyModel(config),
+		lootBoxPolicy:   objects.GetLootBoxSpawnPolicy(config, rng),
+		lootSharing:     objects.GetLootSharingPolicy(config),
+		sightings:       make(map[sightingKey]sighting),
+		elections:       make(map[uuid.UUID][]ElectionDump),
+		sanctionHistory: make(map[uuid.UUID][]SanctionDump),
+		outputOptions:   DefaultOutputOptions(),
+		rng:             rng,
 	}
 	server.replenishLootBoxes()
 	server.replenishMegaBikes()
 
-	return server
+	return server, nil
+}
+
+// spawnAudis creates the audis of the simulation, each with the strategy the config gives it
+func spawnAudis(config utils.SimConfig, rng *rand.Rand) map[uuid.UUID]objects.IAudi {
+	audis := make(map[uuid.UUID]objects.IAudi, config.AudiCount)
+	for i := 0; i < config.AudiCount; i++ {
+		audiConfig := config
+		audiConfig.AudiStrategy = config.AudiStrategyOf(i)
+		audi := objects.GetIAudi(audiConfig, rng)
+		audis[audi.GetID()] = audi
+	}
+	return audis
 }
 
 func (s *Server) RemoveAgent(agent objects.IBaseBiker) {
@@ -111,45 +197,37 @@ func (s *Server) GetDeadAgents() map[uuid.UUID]objects.IBaseBiker {
 	return s.deadAgents
 }
 
-func (s *Server) outputResults(gameStates [][]GameStateDump) {
-	statistics := CalculateStatistics(gameStates)
+func (s *Server) GetConfig() utils.SimConfig {
+	return s.config
+}
 
-	statisticsJson, err := json.MarshalIndent(statistics.Average, "", "    ")
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println("Average Statistics:\n" + string(statisticsJson))
+// SetPhysicsModel replaces the model moving the objects, which is built from the config by default
+func (s *Server) SetPhysicsModel(model physics.IPhysicsModel) {
+	s.physicsEngine.Model = model
+}
 
-	file, err := os.Create("statistics.xlsx")
-	if err != nil {
-		panic(err)
-	}
-	defer file.Close()
-	if err := statistics.ToSpreadsheet().Write(file); err != nil {
-		panic(err)
-	}
+// SetEnergyModel replaces the model giving the energy agents spend, which is built from the config by default
+func (s *Server) SetEnergyModel(model objects.IEnergyModel) {
+	s.energyModel = model
+}
 
-	var flattenedGameStates []GameStateDump
-	for i := range gameStates {
-		flattenedGameStates = append(flattenedGameStates, gameStates[i]...)
-	}
+// SetLootBoxSpawnPolicy replaces the policy placing and replenishing lootboxes, which is built from the config
+// by default. It applies to the lootboxes spawned from then on.
+func (s *Server) SetLootBoxSpawnPolicy(policy objects.ILootBoxSpawnPolicy) {
+	s.lootBoxPolicy = policy
+}
 
-	file, err = os.Create("game_dump.json")
-	if err != nil {
-		panic(err)
-	}
-	defer file.Close()
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "    ")
-	if err := encoder.Encode(flattenedGameStates); err != nil {
-		panic(err)
-	}
+// SetLootSharingPolicy replaces the policy sharing lootboxes between the bikes reaching them at the same time,
+// which is built from the config by default
+func (s *Server) SetLootSharingPolicy(policy objects.ILootSharingPolicy) {
+	s.lootSharing = policy
 }
 
 func (s *Server) UpdateGameStates() {
 	gs := s.NewGameStateDump(0)
-	for _, agent := range s.GetAgentMap() {
-		agent.UpdateGameState(gs)
+	s.forgetSightings()
+	for _, agent := range s.agentsInOrder() {
+		agent.UpdateGameState(s.observedBy(gs, agent))
 	}
 }
 
@@ -157,9 +235,9 @@ func (s *Server) UpdateGameStates() {
 // version of agents, so if the recipients are set to be those it will panic as they
 // can't call the handler functions
 func (s *Server) RunMessagingSession() {
-	agentArray := s.GenerateAgentArrayFromMap()
+	agentArray := s.agentsInOrder()
 
-	for _, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
 		allMessages := agent.GetAllMessages(agentArray)
 		for _, msg := range allMessages {
 			recipients := msg.GetRecipients()
@@ -4,7 +4,6 @@ import (
 	"SOMAS2023/internal/common/objects"
 	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/common/voting"
-	"fmt"
 	"sort"
 
 	"math"
@@ -30,7 +29,7 @@ func (s *Server) RunSimLoop(iterations int) []GameStateDump {
 
 func (s *Server) ResetGameState() {
 	// kick everyone off bikes
-	for _, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
 		if agent.GetBike() != uuid.Nil {
 			s.RemoveAgentFromBike(agent)
 		} else if agent.GetBikeStatus() {
@@ -39,15 +38,16 @@ func (s *Server) ResetGameState() {
 	}
 
 	// respawn people who died in previous round (conditional)
-	if utils.RespawnEveryRound && utils.ReplenishEnergyEveryRound {
-		for _, agent := range s.deadAgents {
+	if s.config.RespawnEveryRound && s.config.ReplenishEnergyEveryRound {
+		for _, agentID := range utils.SortedIDs(s.deadAgents) {
+			agent := s.deadAgents[agentID]
 			s.AddAgent(agent)
 		}
 	}
 
 	// replenish energy (conditional)
-	if utils.ReplenishEnergyEveryRound {
-		for _, agent := range s.GetAgentMap() {
+	if s.config.ReplenishEnergyEveryRound {
+		for _, agent := range s.agentsInOrder() {
 			agent.UpdateEnergyLevel(1.0)
 		}
 	}
@@ -56,20 +56,31 @@ func (s *Server) ResetGameState() {
 	clear(s.deadAgents)
 
 	// zero the points (conditional)
-	if utils.ResetPointsEveryRound {
-		for _, agent := range s.GetAgentMap() {
+	if s.config.ResetPointsEveryRound {
+		for _, agent := range s.agentsInOrder() {
 			agent.ResetPoints()
 		}
 	}
 
-	for _, bike := range s.GetMegaBikes() {
+	// every game starts with the constitution of the simulation config
+	for _, bike := range s.megaBikesInOrder() {
 		bike.SetRuler(uuid.Nil)
+		bike.SetCouncil(nil)
+		bike.AddFinePool(-bike.GetFinePool())
+		bike.SetConstitution(objects.GetConstitution(s.config))
 	}
 
-	for _, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
 		agent.SetBike(uuid.Nil)
 	}
 
+	// every game starts with a full set of lootboxes
+	s.round = 0
+	s.lootShares = nil
+	clear(s.sightings)
+	clear(s.elections)
+	clear(s.sanctionHistory)
+	s.sanctions = nil
 	s.replenishLootBoxes()
 	s.replenishMegaBikes()
 }
@@ -83,7 +94,8 @@ func (s *Server) FoundingInstitutions() {
 
 	// check which governance method is chosen for each biker
 	s.foundingChoices = make(map[uuid.UUID]utils.Governance)
-	for id, agent := range s.GetAgentMap() {
+	for _, agent := range s.agentsInOrder() {
+		id := agent.GetID()
 		// collect choice from each agent
 		choice := agent.DecideGovernance()
 		s.foundingChoices[id] = choice
@@ -97,8 +109,12 @@ func (s *Server) FoundingInstitutions() {
 	// for each governance method, populate megabikes with the bikers who chose that governance method
 	govBikes := make(map[utils.Governance][]uuid.UUID)
 	bikesUsed := make([]uuid.UUID, 0)
-	for governanceMethod, numBikers := range foundingTotals {
-		megaBikesNeeded := int(math.Ceil(float64(numBikers) / float64(utils.BikersOnBike)))
+	for governanceMethod := utils.Democracy; governanceMethod < utils.Invalid; governanceMethod++ {
+		numBikers, ok := foundingTotals[governanceMethod]
+		if !ok {
+			continue
+		}
+		megaBikesNeeded := int(math.Ceil(float64(numBikers) / float64(s.config.BikersOnBike)))
 		govBikes[governanceMethod] = make([]uuid.UUID, megaBikesNeeded)
 		// get bikes for this governance
 		for i := 0; i < megaBikesNeeded; i++ {
@@ -121,14 +137,15 @@ func (s *Server) FoundingInstitutions() {
 		}
 	}
 
-	for agent, governance := range s.foundingChoices {
+	for _, agent := range utils.SortedIDs(s.foundingChoices) {
+		governance := s.foundingChoices[agent]
 		// randomly select a biker from the bikers who chose this governance method
 		// add that biker to a megabike
 		// if there are more bikers for a governance method than there are seats, then evenly distribute them across megabikes
 
 		// select a bike with this governance method which has been assigned the lowest amount of bikers
 		bikesAvailable := govBikes[governance]
-		sort.Slice(bikesAvailable, func(i, j int) bool {
+		sort.SliceStable(bikesAvailable, func(i, j int) bool {
 			// in the order from large to small
 			return len(s.GetMegaBikes()[bikesAvailable[i]].GetAgents()) < len(s.GetMegaBikes()[bikesAvailable[j]].GetAgents())
 		})
@@ -142,13 +159,14 @@ func (s *Server) FoundingInstitutions() {
 	}
 
 	s.UpdateGameStates()
-	// run election process for Leadership and Dictatorship bikes
-	for _, bike := range s.GetMegaBikes() {
+	// run election process for Leadership, Dictatorship and Council bikes
+	for _, bike := range s.megaBikesInOrder() {
 		gov := bike.GetGovernance()
 		agents := bike.GetAgents()
 		if (gov == utils.Leadership || gov == utils.Dictatorship) && len(agents) != 0 {
-			ruler := s.RulerElection(agents, gov)
-			bike.SetRuler(ruler)
+			s.electRuler(bike, gov, FoundingElection, uuid.Nil)
+		} else if gov == utils.Council && len(agents) != 0 {
+			s.electCouncil(bike, FoundingElection, nil)
 		}
 	}
 
@@ -157,18 +175,30 @@ func (s *Server) FoundingInstitutions() {
 }
 
 func (s *Server) Start() {
-	fmt.Printf("Server initialised with %d agents \n\n", len(s.GetAgentMap()))
+	if err := s.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// Run plays every iteration of the game and writes the outputs selected by the output options
+func (s *Server) Run() error {
+	return s.outputResults(s.PlayGames())
+}
+
+// PlayGames plays every iteration of the game and returns the game states of each of them
+func (s *Server) PlayGames() [][]GameStateDump {
+	s.logf("Server initialised with %d agents \n\n", len(s.GetAgentMap()))
 	gameStates := make([][]GameStateDump, 0, s.GetIterations())
 	s.deadAgents = make(map[uuid.UUID]objects.IBaseBiker)
 	for i := 0; i < s.GetIterations(); i++ {
-		fmt.Printf("Game Loop %d running... \n \n", i)
-		fmt.Printf("Main game loop running...\n\n")
-		gameStates = append(gameStates, s.RunSimLoop(utils.RoundIterations))
-		fmt.Printf("\nMain game loop finished.\n\n")
-		fmt.Printf("Messaging session started...\n\n")
+		s.logf("Game Loop %d running... \n \n", i)
+		s.logf("Main game loop running...\n\n")
+		gameStates = append(gameStates, s.RunSimLoop(s.config.RoundIterations))
+		s.logf("\nMain game loop finished.\n\n")
+		s.logf("Messaging session started...\n\n")
 		s.RunMessagingSession()
-		fmt.Printf("\nMessaging session completed\n\n")
-		fmt.Printf("Game Loop %d completed.\n", i)
+		s.logf("\nMessaging session completed\n\n")
+		s.logf("Game Loop %d completed.\n", i)
 	}
-	s.outputResults(gameStates)
+	return gameStates
 }
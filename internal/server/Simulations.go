@@ -0,0 +1,87 @@
+package server
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"fmt"
+	"sync"
+)
+
+// Simulation describes one independent simulation, played on its own Server
+type Simulation struct {
+	Iterations int
+	Config     utils.SimConfig
+	Registry   AgentRegistry // teams the agents are spawned from, nil uses DefaultAgentRegistry
+	Output     OutputOptions // every simulation should write its files to its own directory
+}
+
+// SimulationResult is the outcome of a simulation
+type SimulationResult struct {
+	Seed    int64 // the seed the simulation was played with, picked by the server if the configuration had none
+	Summary RunSummary
+}
+
+// RunSimulations plays the simulations on a pool of workers goroutines and returns their results
+// in the order of the simulations. Servers share no state, so the result of a simulation does not
+// depend on the number of workers. After a simulation fails no further simulation is started and
+// the error of the first failed simulation is returned.
+func RunSimulations(simulations []Simulation, workers int) ([]SimulationResult, error) {
+	workers = max(1, min(workers, len(simulations)))
+	results := make([]SimulationResult, len(simulations))
+	errs := make([]error, len(simulations))
+
+	jobs := make(chan int)
+	var failed sync.Once
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = runSimulation(simulations[i])
+				if errs[i] != nil {
+					failed.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range simulations {
+		select {
+		case jobs <- i:
+		case <-stop:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("simulation %d: %w", i, err)
+		}
+	}
+	return results, nil
+}
+
+func runSimulation(simulation Simulation) (SimulationResult, error) {
+	registry := simulation.Registry
+	if registry == nil {
+		registry = DefaultAgentRegistry()
+	}
+	s, err := newServer(simulation.Iterations, simulation.Config, registry)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	s.SetOutputOptions(simulation.Output)
+
+	gameStates := s.PlayGames()
+	if err := s.outputResults(gameStates); err != nil {
+		return SimulationResult{}, err
+	}
+	return SimulationResult{
+		Seed:    s.config.Seed,
+		Summary: SummariseRun(gameStates),
+	}, nil
+}
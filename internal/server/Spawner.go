@@ -7,6 +7,10 @@ import (
 	"SOMAS2023/internal/clients/team8"
 	"SOMAS2023/internal/common/objects"
 	"SOMAS2023/internal/common/utils"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
 
 	baseserver "github.com/MattSScott/basePlatformSOMAS/BaseServer"
 	"github.com/google/uuid"
@@ -14,25 +18,96 @@ import (
 
 type AgentInitFunction func(baseBiker *objects.BaseBiker) objects.IBaseBiker
 
-var AgentInitFunctions = []AgentInitFunction{
-	nil,                 // Base Biker
-	team1.GetBiker1,     // Team 1
-	team2.GetBiker,      // Team 2
-	team5Agent.GetBiker, // Team 5
-	team8.GetIBaseBiker, // Team 8
+// AgentTeam associates the name used to select a team in the configuration with the
+// constructor of its agents
+type AgentTeam struct {
+	Name     string
+	InitFunc AgentInitFunction
 }
 
-func GetAgentGenerators() []baseserver.AgentGeneratorCountPair[objects.IBaseBiker] {
-	agentGenerators := make([]baseserver.AgentGeneratorCountPair[objects.IBaseBiker], 0, len(AgentInitFunctions))
-	for _, initFunction := range AgentInitFunctions {
-		agentGenerators = append(agentGenerators, baseserver.MakeAgentGeneratorCountPair(BikerAgentGenerator(initFunction), BikerAgentCount/len(AgentInitFunctions)))
+// AgentRegistry lists the teams that can be spawned, agents are spawned in this order. Each
+// server keeps its own registry so that simulations running side by side cannot affect each other.
+type AgentRegistry []AgentTeam
+
+// DefaultAgentRegistry returns a new registry holding every team
+func DefaultAgentRegistry() AgentRegistry {
+	return AgentRegistry{
+		{Name: "base", InitFunc: nil}, // Base Biker
+		{Name: "team1", InitFunc: team1.GetBiker1},
+		{Name: "team2", InitFunc: team2.GetBiker},
+		{Name: "team5", InitFunc: team5Agent.GetBiker},
+		{Name: "team8", InitFunc: team8.GetIBaseBiker},
+	}
+}
+
+// AgentTeamNames returns the names of the teams of the default registry
+func AgentTeamNames() []string {
+	return DefaultAgentRegistry().Names()
+}
+
+// GetTeamCounts returns the number of agents to spawn for every team of the default registry
+func GetTeamCounts(config utils.SimConfig) (map[string]int, error) {
+	return DefaultAgentRegistry().TeamCounts(config)
+}
+
+// Names returns the names of the registered teams
+func (r AgentRegistry) Names() []string {
+	names := make([]string, len(r))
+	for i, team := range r {
+		names[i] = team.Name
+	}
+	return names
+}
+
+// TeamCounts returns the number of agents to spawn for every registered team. Without
+// config.TeamCounts, config.BikerAgentCount is split as evenly as possible over the teams.
+func (r AgentRegistry) TeamCounts(config utils.SimConfig) (map[string]int, error) {
+	names := r.Names()
+	teamCounts := make(map[string]int, len(names))
+	if len(config.TeamCounts) == 0 {
+		if len(names) == 0 {
+			return teamCounts, nil
+		}
+		for i, name := range names {
+			teamCounts[name] = config.BikerAgentCount / len(names)
+			if i < config.BikerAgentCount%len(names) {
+				teamCounts[name]++
+			}
+		}
+		return teamCounts, nil
+	}
+
+	unknownTeams := make([]string, 0)
+	for name, count := range config.TeamCounts {
+		if !slices.Contains(names, name) {
+			unknownTeams = append(unknownTeams, name)
+		}
+		teamCounts[name] = count
+	}
+	if len(unknownTeams) > 0 {
+		sort.Strings(unknownTeams)
+		return nil, fmt.Errorf("unknown teams %v, the registered teams are %v", unknownTeams, names)
+	}
+	return teamCounts, nil
+}
+
+func (r AgentRegistry) GetAgentGenerators(config utils.SimConfig, rng *rand.Rand) ([]baseserver.AgentGeneratorCountPair[objects.IBaseBiker], error) {
+	teamCounts, err := r.TeamCounts(config)
+	if err != nil {
+		return nil, err
+	}
+	agentGenerators := make([]baseserver.AgentGeneratorCountPair[objects.IBaseBiker], 0, len(r))
+	for _, team := range r {
+		if count := teamCounts[team.Name]; count > 0 {
+			agentGenerators = append(agentGenerators, baseserver.MakeAgentGeneratorCountPair(BikerAgentGenerator(team.InitFunc, rng), count))
+		}
 	}
-	return agentGenerators
+	return agentGenerators, nil
 }
 
-func BikerAgentGenerator(initFunc func(baseBiker *objects.BaseBiker) objects.IBaseBiker) func() objects.IBaseBiker {
+func BikerAgentGenerator(initFunc func(baseBiker *objects.BaseBiker) objects.IBaseBiker, rng *rand.Rand) func() objects.IBaseBiker {
 	return func() objects.IBaseBiker {
-		baseBiker := objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New())
+		baseBiker := objects.GetBaseBiker(utils.NumOfColours, uuid.Nil, rng)
 		if initFunc == nil {
 			return baseBiker
 		} else {
@@ -42,24 +117,24 @@ func BikerAgentGenerator(initFunc func(baseBiker *objects.BaseBiker) objects.IBa
 }
 
 func (s *Server) spawnLootBox() {
-	lootBox := objects.GetLootBox()
+	lootBox := objects.SpawnLootBox(s.config, s.rng, s.lootBoxPolicy)
 	s.lootBoxes[lootBox.GetID()] = lootBox
 }
 
 func (s *Server) replenishLootBoxes() {
-	count := LootBoxCount - len(s.lootBoxes)
+	count := s.config.LootBoxCount - len(s.lootBoxes)
 	for i := 0; i < count; i++ {
 		s.spawnLootBox()
 	}
 }
 
 func (s *Server) spawnMegaBike() {
-	megaBike := objects.GetMegaBike()
+	megaBike := objects.GetMegaBike(s.config, s.rng)
 	s.megaBikes[megaBike.GetID()] = megaBike
 }
 
 func (s *Server) replenishMegaBikes() {
-	neededBikes := MegaBikeCount - len(s.megaBikes)
+	neededBikes := s.config.MegaBikeCount - len(s.megaBikes)
 	for i := 0; i < neededBikes; i++ {
 		s.spawnMegaBike()
 	}
@@ -1,12 +1,15 @@
 package server_test
 
 import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/physics"
 	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/server"
 	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAudiCollisionProcess(t *testing.T) {
@@ -104,3 +107,333 @@ func TestAudiTargeting(t *testing.T) {
 	}
 	fmt.Printf("\nRun action process passed \n")
 }
+
+func TestAudiCannotDriveThroughBikes(t *testing.T) {
+	s := server.Initialize(1)
+	s.FoundingInstitutions()
+	for _, bike := range s.GetMegaBikes() {
+		if len(bike.GetAgents()) == 0 {
+			continue
+		}
+		// the audi starts and ends its step far from the bike, which is in the middle of its path
+		position := bike.GetPosition()
+		audi := s.GetAudi()
+		audi.SetPhysicalState(utils.PhysicalState{Position: utils.Coordinates{X: position.X - 40, Y: position.Y}, Mass: utils.MassAudi})
+		audi.SetPhysicalState(utils.PhysicalState{Position: utils.Coordinates{X: position.X + 40, Y: position.Y}, Mass: utils.MassAudi})
+
+		agentsOnBike := bike.GetAgents()
+		s.AudiCollisionCheck()
+		for _, agent := range agentsOnBike {
+			if _, alive := s.GetAgentMap()[agent.GetID()]; alive {
+				t.Errorf("agent %s should have been killed by the audi", agent.GetID())
+			}
+		}
+		return
+	}
+	t.Fatal("no bike has any riders")
+}
+
+// audiWithStrategy returns a server whose audi uses the given strategy, with the agents on their bikes
+func audiWithStrategy(t *testing.T, strategy utils.AudiTargetingStrategy) server.IBaseBikerServer {
+	config := utils.DefaultSimConfig()
+	config.AudiStrategy = strategy
+	config.Seed = 5
+	s, err := server.InitializeWithConfig(1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.FoundingInstitutions()
+	return s
+}
+
+// targetOf makes the audi pick a target in the current state of the game
+func targetOf(s server.IBaseBikerServer) uuid.UUID {
+	s.GetAudi().UpdateGameState(s.NewGameStateDump(0))
+	s.GetAudi().UpdateForce()
+	return s.GetAudi().GetTargetID()
+}
+
+// bestBike returns the non empty bike with the highest value, then the nearest to the audi
+func bestBike(s server.IBaseBikerServer, value func(objects.IMegaBike) float64) uuid.UUID {
+	best := uuid.Nil
+	var bestValue, bestDistance float64
+	for _, id := range utils.SortedIDs(s.GetMegaBikes()) {
+		bike := s.GetMegaBikes()[id]
+		if len(bike.GetAgents()) == 0 {
+			continue
+		}
+		distance := physics.ComputeDistance(s.GetAudi().GetPosition(), bike.GetPosition())
+		if best == uuid.Nil || value(bike) > bestValue || (value(bike) == bestValue && distance < bestDistance) {
+			best, bestValue, bestDistance = id, value(bike), distance
+		}
+	}
+	return best
+}
+
+func TestAudiStrategies(t *testing.T) {
+	s := audiWithStrategy(t, utils.TargetNearest)
+	assert.Equal(t, bestBike(s, func(objects.IMegaBike) float64 { return 0 }), targetOf(s))
+
+	s = audiWithStrategy(t, utils.TargetMostPopulated)
+	population := func(bike objects.IMegaBike) float64 { return float64(len(bike.GetAgents())) }
+	assert.Equal(t, bestBike(s, population), targetOf(s))
+
+	s = audiWithStrategy(t, utils.TargetRichest)
+	rich := bestBike(s, func(objects.IMegaBike) float64 { return 0 })
+	for _, id := range utils.SortedIDs(s.GetMegaBikes()) {
+		if agents := s.GetMegaBikes()[id].GetAgents(); len(agents) > 0 && id != rich {
+			// the furthest bikes are richer than the nearest one
+			agents[0].UpdatePoints(10)
+		}
+	}
+	points := func(bike objects.IMegaBike) float64 {
+		total := 0
+		for _, agent := range bike.GetAgents() {
+			total += agent.GetPoints()
+		}
+		return float64(total)
+	}
+	richest := bestBike(s, points)
+	assert.NotEqual(t, rich, richest)
+	assert.Equal(t, richest, targetOf(s))
+}
+
+func TestAudiRoundRobinStrategy(t *testing.T) {
+	s := audiWithStrategy(t, utils.TargetRoundRobin)
+	candidates := make([]uuid.UUID, 0)
+	for _, id := range utils.SortedIDs(s.GetMegaBikes()) {
+		if len(s.GetMegaBikes()[id].GetAgents()) > 0 {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) < 2 {
+		t.Fatal("at least two bikes should have riders")
+	}
+
+	assert.Equal(t, candidates[0], targetOf(s))
+	assert.Equal(t, candidates[0], targetOf(s), "the audi should keep chasing its target")
+	// once the audi reaches its target it moves on to the next bike
+	s.GetAudi().SetPhysicalState(utils.PhysicalState{Position: s.GetMegaBikes()[candidates[0]].GetPosition(), Mass: utils.MassAudi})
+	assert.Equal(t, candidates[1], targetOf(s))
+}
+
+func TestAudiRoundRobinAfterCooldown(t *testing.T) {
+	s := audiWithStrategy(t, utils.TargetRoundRobin)
+	candidates := make([]uuid.UUID, 0)
+	for _, id := range utils.SortedIDs(s.GetMegaBikes()) {
+		if len(s.GetMegaBikes()[id].GetAgents()) > 0 {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) < 3 {
+		t.Fatal("at least three bikes should have riders")
+	}
+
+	assert.Equal(t, candidates[0], targetOf(s))
+	// the audi hits its target and idles for a round, forgetting its target
+	s.GetAudi().SetPhysicalState(utils.PhysicalState{Position: s.GetMegaBikes()[candidates[0]].GetPosition(), Mass: utils.MassAudi})
+	s.GetAudi().SetCooldown(1)
+	assert.Equal(t, uuid.Nil, targetOf(s), "the audi should not chase bikes while it cools down")
+	s.GetAudi().SetCooldown(0)
+	assert.Equal(t, candidates[1], targetOf(s), "the audi should carry on with the next bike after its cooldown")
+
+	s.GetAudi().SetPhysicalState(utils.PhysicalState{Position: s.GetMegaBikes()[candidates[1]].GetPosition(), Mass: utils.MassAudi})
+	s.GetAudi().SetCooldown(1)
+	assert.Equal(t, uuid.Nil, targetOf(s))
+	s.GetAudi().SetCooldown(0)
+	assert.Equal(t, candidates[2], targetOf(s))
+}
+
+func TestAudiRandomStrategy(t *testing.T) {
+	s := audiWithStrategy(t, utils.TargetRandom)
+	target := targetOf(s)
+	assert.NotEmpty(t, s.GetMegaBikes()[target].GetAgents(), "the audi should target a bike with riders")
+	assert.Equal(t, target, targetOf(s), "the audi should keep chasing its target")
+}
+
+func TestAudiInterceptsMovingBike(t *testing.T) {
+	for _, pursuit := range []utils.PursuitMode{utils.DirectPursuit, utils.InterceptPursuit} {
+		config := utils.DefaultSimConfig()
+		config.AudiStrategy = utils.TargetNearest
+		config.AudiPursuit = pursuit
+		config.Seed = 5
+		s, err := server.InitializeWithConfig(1, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.FoundingInstitutions()
+
+		// the bike drives up while the audi comes from its left
+		target := s.GetMegaBikes()[bestBike(s, func(objects.IMegaBike) float64 { return 0 })]
+		target.SetPhysicalState(utils.PhysicalState{Position: target.GetPosition(), Velocity: 1, Mass: utils.MassBike})
+		target.SetOrientation(0.5)
+		audiPosition := utils.Coordinates{X: target.GetPosition().X - 5, Y: target.GetPosition().Y}
+		s.GetAudi().SetPhysicalState(utils.PhysicalState{Position: audiPosition, Velocity: 2, Mass: utils.MassAudi})
+
+		assert.Equal(t, target.GetID(), targetOf(s))
+		s.GetAudi().UpdateOrientation()
+		if pursuit == utils.DirectPursuit {
+			assert.Equal(t, 0.0, s.GetAudi().GetOrientation(), "the audi should head straight for the bike")
+		} else {
+			assert.Greater(t, s.GetAudi().GetOrientation(), 0.0, "the audi should lead the bike")
+			assert.Less(t, s.GetAudi().GetOrientation(), 0.5)
+		}
+	}
+}
+
+func TestMultipleAudis(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.AudiCount = 3
+	config.AudiStrategies = []utils.AudiTargetingStrategy{utils.TargetNearest}
+	config.Seed = 5
+	s, err := server.InitializeWithConfig(1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.FoundingInstitutions()
+
+	audis := s.GetAudis()
+	assert.Len(t, audis, 3)
+	positions := make(map[utils.Coordinates]bool)
+	for id, audi := range audis {
+		assert.Equal(t, id, audi.GetID())
+		positions[audi.GetPosition()] = true
+	}
+	assert.Len(t, positions, 3, "audis should spawn at their own positions")
+	assert.Equal(t, utils.SortedIDs(audis)[0], s.GetAudi().GetID(), "GetAudi should return the first audi")
+
+	gs := s.NewGameStateDump(0)
+	assert.Len(t, gs.GetAudis(), 3)
+	assert.Equal(t, s.GetAudi().GetID(), gs.GetAudi().GetID())
+
+	// every audi kills the riders of the bike it hits
+	hit := make([]objects.IMegaBike, 0)
+	for _, id := range utils.SortedIDs(s.GetMegaBikes()) {
+		if bike := s.GetMegaBikes()[id]; len(bike.GetAgents()) > 0 && len(hit) < 2 {
+			hit = append(hit, bike)
+		}
+	}
+	if len(hit) < 2 {
+		t.Fatal("at least two bikes should have riders")
+	}
+	victims := make([]objects.IBaseBiker, 0)
+	for i, id := range utils.SortedIDs(audis)[:2] {
+		audis[id].SetPhysicalState(utils.PhysicalState{Position: hit[i].GetPosition(), Mass: utils.MassAudi})
+		victims = append(victims, hit[i].GetAgents()...)
+	}
+	// the last audi stays far from every bike during its whole step
+	farAway := utils.Coordinates{X: -1000, Y: -1000}
+	audis[utils.SortedIDs(audis)[2]].SetPhysicalState(utils.PhysicalState{Position: farAway, Mass: utils.MassAudi})
+	audis[utils.SortedIDs(audis)[2]].SetPhysicalState(utils.PhysicalState{Position: farAway, Mass: utils.MassAudi})
+	s.AudiCollisionCheck()
+	for _, agent := range victims {
+		_, alive := s.GetAgentMap()[agent.GetID()]
+		assert.False(t, alive, "agent %s should have been killed by an audi", agent.GetID())
+	}
+}
+
+// audiOnBike returns a server with the given config whose audi sits on a bike with riders, moving at audiVelocity
+func audiOnBike(t *testing.T, config utils.SimConfig, audiVelocity float64) (server.IBaseBikerServer, objects.IMegaBike) {
+	config.Seed = 5
+	s, err := server.InitializeWithConfig(1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.FoundingInstitutions()
+	for _, id := range utils.SortedIDs(s.GetMegaBikes()) {
+		bike := s.GetMegaBikes()[id]
+		if len(bike.GetAgents()) == 0 {
+			continue
+		}
+		s.GetAudi().SetPhysicalState(utils.PhysicalState{Position: bike.GetPosition(), Velocity: audiVelocity, Mass: utils.MassAudi})
+		s.GetAudi().SetPhysicalState(utils.PhysicalState{Position: bike.GetPosition(), Velocity: audiVelocity, Mass: utils.MassAudi})
+		return s, bike
+	}
+	t.Fatal("no bike has any riders")
+	return nil, nil
+}
+
+func TestAudiCollisionOutcomes(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.AudiCollision = utils.DamageRiders
+	config.AudiDamage = 0.25
+	s, bike := audiOnBike(t, config, 3.0)
+	bike.SetPhysicalState(utils.PhysicalState{Position: bike.GetPosition(), Velocity: 1.0, Mass: bike.GetPhysicalState().Mass})
+	riders := bike.GetAgents()
+	energies := make(map[uuid.UUID]float64)
+	for _, agent := range riders {
+		energies[agent.GetID()] = agent.GetEnergyLevel()
+	}
+	s.AudiCollisionCheck()
+	for _, agent := range riders {
+		_, alive := s.GetAgentMap()[agent.GetID()]
+		assert.True(t, alive, "damaged riders should survive the hit")
+		assert.InDelta(t, energies[agent.GetID()]-0.5, agent.GetEnergyLevel(), 1e-9, "damage should grow with the speed of the audi over the bike")
+	}
+
+	for _, probability := range []float64{0.0, 1.0} {
+		config := utils.DefaultSimConfig()
+		config.AudiCollision = utils.KillRandomRiders
+		config.AudiKillProbability = probability
+		s, bike := audiOnBike(t, config, 0.0)
+		riders := bike.GetAgents()
+		s.AudiCollisionCheck()
+		for _, agent := range riders {
+			_, alive := s.GetAgentMap()[agent.GetID()]
+			assert.Equal(t, probability == 0.0, alive, "kill probability %v", probability)
+		}
+	}
+}
+
+func TestAudiShieldBlocksHit(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.AudiShieldCost = 1.0
+	s, bike := audiOnBike(t, config, 0.0)
+	bike.AddShield(1.5)
+	riders := bike.GetAgents()
+	s.AudiCollisionCheck()
+	for _, agent := range riders {
+		_, alive := s.GetAgentMap()[agent.GetID()]
+		assert.True(t, alive, "the shield should protect the riders")
+	}
+	assert.Equal(t, 0.5, bike.GetShield())
+
+	// the shield is too weak to block a second hit
+	s.AudiCollisionCheck()
+	for _, agent := range riders {
+		_, alive := s.GetAgentMap()[agent.GetID()]
+		assert.False(t, alive, "the riders should have been killed once the shield wore off")
+	}
+}
+
+func TestAudiCooldownAndRespawn(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.AudiCollision = utils.DamageRiders
+	config.AudiCooldown = 2
+	s, bike := audiOnBike(t, config, 2.0)
+	audi := s.GetAudi()
+	s.AudiCollisionCheck()
+	assert.Equal(t, 2, audi.GetCooldown())
+	assert.Equal(t, 0.0, audi.GetVelocity(), "the audi should stop after a hit")
+	assert.Equal(t, bike.GetPosition(), audi.GetPosition())
+
+	// the audi idles on the spot until it has cooled down
+	energy := bike.GetAgents()[0].GetEnergyLevel()
+	audi.UpdateGameState(s.NewGameStateDump(0))
+	audi.UpdateForce()
+	assert.Equal(t, 0.0, audi.GetForce())
+	assert.Equal(t, uuid.Nil, audi.GetTargetID())
+	s.AudiCollisionCheck()
+	s.AudiCollisionCheck()
+	assert.Equal(t, 0, audi.GetCooldown())
+	assert.Equal(t, energy, bike.GetAgents()[0].GetEnergyLevel(), "a cooling audi should not hurt anyone")
+	audi.UpdateForce()
+	assert.Equal(t, config.AudiMaxForce, audi.GetForce(), "the audi should chase bikes again once cooled down")
+
+	config.AudiCooldown = 0
+	config.AudiRespawns = true
+	s, bike = audiOnBike(t, config, 2.0)
+	s.AudiCollisionCheck()
+	assert.NotEqual(t, bike.GetPosition(), s.GetAudi().GetPosition(), "the audi should respawn elsewhere")
+	assert.Equal(t, 0, s.GetAudi().GetCooldown())
+}
@@ -0,0 +1,67 @@
+package server_test
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"SOMAS2023/internal/server"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperimentParameterSets(t *testing.T) {
+	experiment := server.Experiment{
+		Grid: map[string][]any{
+			"vote_action":          {"plurality", "borda_count"},
+			"limbo_energy_penalty": {-0.1, -0.2, -0.3},
+		},
+		Runs: []map[string]any{
+			{"team_counts": map[string]any{"team1": 4}},
+			{"team_counts": map[string]any{"team5": 4}},
+		},
+	}
+	parameterSets := experiment.ParameterSets()
+	assert.Len(t, parameterSets, 12, "every run should be combined with every grid combination")
+	assert.Equal(t, "limbo_energy_penalty=-0.1 team_counts=team1:4 vote_action=plurality", parameterSets[0].String())
+	assert.Equal(t, "limbo_energy_penalty=-0.1 team_counts=team1:4 vote_action=borda_count", parameterSets[1].String())
+	assert.Equal(t, "limbo_energy_penalty=-0.3 team_counts=team5:4 vote_action=borda_count", parameterSets[11].String())
+	assert.Equal(t, []string{"limbo_energy_penalty", "team_counts", "vote_action"}, server.ParameterNames(parameterSets))
+
+	assert.Len(t, server.Experiment{}.ParameterSets(), 1, "an experiment without parameters plays the base configuration")
+}
+
+func TestRunExperiment(t *testing.T) {
+	experiment := server.Experiment{
+		Iterations: 1,
+		Seeds:      "1-2",
+		Base:       map[string]any{"round_iterations": 5, "team_counts": map[string]any{"base": 8}},
+		Grid:       map[string][]any{"vote_action": {"plurality", "borda_count"}},
+	}
+	options := server.DefaultOutputOptions()
+	options.Statistics = false
+	options.GameDump = false
+
+	runs, err := server.RunExperiment(experiment, utils.DefaultSimConfig(), options, 2)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 4)
+
+	results := server.AggregateRuns(runs)
+	assert.Len(t, results, 2, "the runs should be grouped by parameter set")
+	for _, result := range results {
+		assert.Equal(t, 2, result.Seeds)
+		assert.Equal(t, 8, result.Mean.Agents)
+		assert.GreaterOrEqual(t, result.Mean.SurvivalRate, 0.0)
+		assert.LessOrEqual(t, result.Mean.SurvivalRate, 1.0)
+	}
+
+	directory := t.TempDir()
+	assert.NoError(t, server.WriteExperimentResults(directory, runs))
+	for _, name := range []string{server.ExperimentResultsFileName, server.ExperimentRunsFileName} {
+		_, err := os.Stat(filepath.Join(directory, name))
+		assert.NoError(t, err)
+	}
+
+	_, err = server.RunExperiment(server.Experiment{Iterations: 1, Seeds: "1", Base: map[string]any{"grid_wdth": 1}}, utils.DefaultSimConfig(), options, 2)
+	assert.Error(t, err, "unknown parameters should be reported before any simulation is run")
+}
@@ -1,8 +1,13 @@
 package server_test
 
 import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/physics"
+	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/server"
+	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 
 	"slices"
@@ -145,3 +150,126 @@ func TestAddAgentToBike(t *testing.T) {
 	}
 	fmt.Printf("\nSet biker bike passed \n")
 }
+
+func TestGameStateProximityQueries(t *testing.T) {
+	s := server.Initialize(1)
+	gs := s.NewGameStateDump(0)
+	origin := utils.Coordinates{X: 0, Y: 0}
+	distance := func(id uuid.UUID) float64 {
+		return math.Sqrt(physics.ComputeDistance(origin, gs.LootBoxes[id].GetPosition()))
+	}
+
+	nearest := gs.GetNearestLootBoxes(origin, len(gs.LootBoxes))
+	assert.Len(t, nearest, len(gs.LootBoxes))
+	for i := 1; i < len(nearest); i++ {
+		assert.LessOrEqual(t, distance(nearest[i-1]), distance(nearest[i]))
+	}
+
+	radius := distance(nearest[0]) + 1
+	for _, id := range gs.GetLootBoxesWithin(origin, radius) {
+		assert.Less(t, distance(id), radius)
+	}
+	for _, id := range gs.GetMegaBikesWithin(origin, 1000) {
+		assert.Contains(t, gs.Bikes, id)
+	}
+
+	colour := gs.LootBoxes[nearest[len(nearest)-1]].GetColour()
+	for _, id := range gs.GetNearestLootBoxesOfColour(origin, 3, colour) {
+		assert.Equal(t, colour, gs.LootBoxes[id].GetColour())
+	}
+}
+
+func TestLoadedGameStateProximityQueries(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.Boundary = utils.Torus
+	s, err := server.InitializeWithConfig(1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := s.NewGameStateDump(0)
+	data, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded server.GameStateDump
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	// the queries wrap around the edges of the grid like those of the server
+	origin := utils.Coordinates{X: 0, Y: 0}
+	assert.Equal(t, gs.GetNearestLootBoxes(origin, 5), loaded.GetNearestLootBoxes(origin, 5))
+	assert.Equal(t, gs.GetLootBoxesWithin(origin, 200), loaded.GetLootBoxesWithin(origin, 200))
+	assert.Equal(t, gs.GetMegaBikesWithin(origin, 200), loaded.GetMegaBikesWithin(origin, 200))
+}
+
+// observeLootBoxes puts the first agent on a bike next to the first lootbox and returns the game state
+// it and an agent off a bike are given
+func observeLootBoxes(t *testing.T, config utils.SimConfig) (server.IBaseBikerServer, objects.IMegaBike, objects.IGameState, objects.IGameState) {
+	s, err := server.InitializeWithRegistry(1, config, server.AgentRegistry{{Name: "base", InitFunc: nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lootbox := s.GetLootBoxes()[utils.SortedIDs(s.GetLootBoxes())[0]]
+	bike := s.GetMegaBikes()[utils.SortedIDs(s.GetMegaBikes())[0]]
+	moveTo(bike, lootbox.GetPosition(), lootbox.GetPosition())
+
+	agentIDs := utils.SortedIDs(s.GetAgentMap())
+	rider, walker := s.GetAgentMap()[agentIDs[0]], s.GetAgentMap()[agentIDs[1]]
+	rider.SetBike(bike.GetID())
+	s.AddAgentToBike(rider)
+	s.UpdateGameStates()
+	return s, bike, rider.(*objects.BaseBiker).GetGameState(), walker.(*objects.BaseBiker).GetGameState()
+}
+
+func TestHiddenLootBoxes(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootBoxResourceObservation = utils.HiddenObservation
+	config.LootBoxColourObservation = utils.HiddenObservation
+	s, bike, riderView, walkerView := observeLootBoxes(t, config)
+
+	for id, lootbox := range s.GetLootBoxes() {
+		distance := physics.NewBoundary(config).ComputeDistance(bike.GetPosition(), lootbox.GetPosition())
+		if distance < math.Pow(config.LootBoxRevealRadius, 2) {
+			assert.Equal(t, lootbox.GetTotalResources(), riderView.GetLootBoxes()[id].GetTotalResources(), "lootboxes near the bike should be revealed")
+			assert.Equal(t, lootbox.GetColour(), riderView.GetLootBoxes()[id].GetColour())
+		} else {
+			assert.Equal(t, utils.HiddenResources, riderView.GetLootBoxes()[id].GetTotalResources())
+			assert.Equal(t, utils.HiddenColour, riderView.GetLootBoxes()[id].GetColour())
+		}
+		assert.Equal(t, lootbox.GetPosition(), riderView.GetLootBoxes()[id].GetPosition(), "positions should not be hidden")
+		assert.Equal(t, utils.HiddenResources, walkerView.GetLootBoxes()[id].GetTotalResources(), "agents off a bike should not see into any lootbox")
+		assert.Equal(t, utils.HiddenColour, walkerView.GetLootBoxes()[id].GetColour())
+	}
+	// the dump of the game keeps the actual lootboxes
+	for id, lootbox := range s.NewGameStateDump(0).LootBoxes {
+		assert.Equal(t, s.GetLootBoxes()[id].GetTotalResources(), lootbox.TotalResources)
+	}
+}
+
+func TestNoisyLootBoxes(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootBoxResourceObservation = utils.NoisyObservation
+	config.LootBoxColourObservation = utils.NoisyObservation
+	config.LootBoxRevealRadius = 0
+	s, _, riderView, walkerView := observeLootBoxes(t, config)
+
+	blurred := 0
+	for id, lootbox := range s.GetLootBoxes() {
+		seen := riderView.GetLootBoxes()[id]
+		assert.GreaterOrEqual(t, seen.GetTotalResources(), 0.0)
+		if seen.GetTotalResources() != lootbox.GetTotalResources() {
+			blurred++
+		}
+		assert.NotEqual(t, seen.GetTotalResources(), walkerView.GetLootBoxes()[id].GetTotalResources(), "every bike should get its own noise")
+	}
+	assert.Equal(t, len(s.GetLootBoxes()), blurred)
+
+	// looking again gives the same sighting
+	s.UpdateGameStates()
+	rider := s.GetAgentMap()[utils.SortedIDs(s.GetAgentMap())[0]].(*objects.BaseBiker)
+	for id, lootbox := range riderView.GetLootBoxes() {
+		assert.Equal(t, lootbox.GetTotalResources(), rider.GetGameState().GetLootBoxes()[id].GetTotalResources())
+		assert.Equal(t, lootbox.GetColour(), rider.GetGameState().GetLootBoxes()[id].GetColour())
+	}
+}
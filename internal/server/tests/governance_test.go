@@ -1,12 +1,17 @@
 package server_test
 
 import (
+	"SOMAS2023/internal/common/objects"
 	"SOMAS2023/internal/common/utils"
+	"SOMAS2023/internal/common/voting"
 	"SOMAS2023/internal/server"
 	"fmt"
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRulerElectionDictator(t *testing.T) {
@@ -164,3 +169,500 @@ func TestRunDemocraticAction(t *testing.T) {
 	}
 	fmt.Printf("\nDemocratic action passed \n")
 }
+
+// citizen founds a bike with a set governance and votes for another in referendums, may propose and approve
+// amendments, may have no confidence in its ruler, may vote for a set leader and may vote to kick out or sanction a rider
+type citizen struct {
+	*objects.BaseBiker
+	founding     utils.Governance
+	vote         utils.Governance
+	petition     bool
+	amendment    *objects.Constitution
+	approves     bool
+	noConfidence bool
+	ousts        uuid.UUID
+	accuses      uuid.UUID
+	leader       voting.IdVoteMap
+	leaves       bool
+}
+
+func (c *citizen) DecideAction() objects.BikerAction {
+	if c.leaves {
+		return objects.ChangeBike
+	}
+	return c.BaseBiker.DecideAction()
+}
+
+func (c *citizen) VoteLeader() voting.IdVoteMap {
+	if c.leader == nil {
+		return c.BaseBiker.VoteLeader()
+	}
+	return maps.Clone(c.leader)
+}
+
+func (c *citizen) VoteForSanction() map[uuid.UUID]int {
+	if c.accuses == uuid.Nil {
+		return map[uuid.UUID]int{}
+	}
+	return map[uuid.UUID]int{c.accuses: 1}
+}
+
+func (c *citizen) DecideSanctions() []uuid.UUID {
+	if c.accuses == uuid.Nil {
+		return []uuid.UUID{}
+	}
+	return []uuid.UUID{c.accuses}
+}
+
+func (c *citizen) VoteForKickout() map[uuid.UUID]int {
+	if c.ousts == uuid.Nil {
+		return map[uuid.UUID]int{}
+	}
+	return map[uuid.UUID]int{c.ousts: 1}
+}
+
+func (c *citizen) DecideGovernance() utils.Governance {
+	return c.founding
+}
+
+func (c *citizen) DecideImpeachment() bool {
+	return c.noConfidence
+}
+
+func (c *citizen) ProposeAmendment(constitution objects.Constitution) (objects.Constitution, bool) {
+	if c.amendment == nil {
+		return constitution, false
+	}
+	return *c.amendment, true
+}
+
+func (c *citizen) VoteAmendment(objects.Constitution, objects.Constitution) bool {
+	return c.approves
+}
+
+func (c *citizen) VoteGovernance() voting.GovernanceVote {
+	return voting.GovernanceVote{c.vote: 1.0}
+}
+
+func (c *citizen) PetitionReferendum() bool {
+	return c.petition
+}
+
+func initializeCitizens(t *testing.T, config utils.SimConfig) server.IBaseBikerServer {
+	registry := server.AgentRegistry{{Name: "base", InitFunc: func(baseBiker *objects.BaseBiker) objects.IBaseBiker {
+		return &citizen{BaseBiker: baseBiker, vote: utils.Democracy}
+	}}}
+	s, err := server.InitializeWithRegistry(3, config, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestPetitionedReferendum(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.ReferendumPetition = 0.5
+	s := initializeCitizens(t, config)
+	bike := s.GetMegaBikes()[utils.SortedIDs(s.GetMegaBikes())[0]]
+	citizens := make([]*citizen, 0)
+	for _, id := range utils.SortedIDs(s.GetAgentMap())[:4] {
+		rider := s.GetAgentMap()[id]
+		rider.SetBike(bike.GetID())
+		s.AddAgentToBike(rider)
+		citizens = append(citizens, rider.(*citizen))
+	}
+	s.UpdateGameStates()
+
+	for _, c := range citizens[:3] {
+		c.vote = utils.Dictatorship
+	}
+	s.RunReferendums()
+	assert.Equal(t, utils.Democracy, bike.GetGovernance(), "there should be no referendum without a petition")
+
+	citizens[0].petition = true
+	s.RunReferendums()
+	assert.Equal(t, utils.Democracy, bike.GetGovernance(), "a quarter of the riders should not be enough to call a referendum")
+
+	citizens[1].petition = true
+	citizens[2].vote = utils.Leadership
+	s.RunReferendums()
+	assert.Equal(t, utils.Democracy, bike.GetGovernance(), "half of the votes should not be a supermajority")
+
+	citizens[2].vote = utils.Dictatorship
+	s.RunReferendums()
+	assert.Equal(t, utils.Dictatorship, bike.GetGovernance())
+	assert.Contains(t, utils.SortedIDs(s.GetAgentMap())[:4], bike.GetRuler(), "a dictator should have been elected amongst the riders")
+
+	for _, c := range citizens {
+		c.vote = utils.Democracy
+	}
+	s.RunReferendums()
+	assert.Equal(t, utils.Democracy, bike.GetGovernance())
+	assert.Equal(t, uuid.Nil, bike.GetRuler())
+}
+
+func TestScheduledReferendum(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.ReferendumPeriod = 2
+	config.RoundIterations = 3
+	s := initializeCitizens(t, config)
+	s.SetOutputOptions(server.OutputOptions{})
+	for _, agent := range s.GetAgentMap() {
+		agent.(*citizen).vote = utils.Leadership
+	}
+
+	gameStates := s.PlayGames()[0]
+	assert.Len(t, gameStates, config.RoundIterations+1)
+	for round, gameState := range gameStates {
+		for id, bike := range gameState.Bikes {
+			if len(bike.AgentIDs) == 0 {
+				continue
+			}
+			if round < 3 {
+				assert.NotEqual(t, utils.Leadership, bike.Governance, "no referendum should be held before round 2")
+			} else {
+				assert.Equal(t, utils.Leadership, bike.Governance, "bike %s should have voted for a leadership", id)
+				assert.Contains(t, bike.AgentIDs, bike.Ruler)
+			}
+		}
+	}
+}
+
+func TestAmendments(t *testing.T) {
+	s := initializeCitizens(t, utils.DefaultSimConfig())
+	bike := s.GetMegaBikes()[utils.SortedIDs(s.GetMegaBikes())[0]]
+	citizens := make([]*citizen, 0)
+	for _, id := range utils.SortedIDs(s.GetAgentMap())[:4] {
+		rider := s.GetAgentMap()[id]
+		rider.SetBike(bike.GetID())
+		s.AddAgentToBike(rider)
+		citizens = append(citizens, rider.(*citizen))
+	}
+	s.UpdateGameStates()
+	original := bike.GetConstitution()
+
+	amendment := original
+	amendment.RulerTerm = 3
+	citizens[0].amendment = &amendment
+	citizens[0].approves, citizens[1].approves = true, true
+	s.RunAmendments()
+	assert.Equal(t, original, bike.GetConstitution(), "half the votes should not be enough to amend the constitution")
+
+	citizens[2].approves = true
+	s.RunAmendments()
+	assert.Equal(t, amendment, bike.GetConstitution())
+
+	citizens[3].approves = true
+	invalid := amendment
+	invalid.AdmissionQuorum = 2
+	citizens[0].amendment = &invalid
+	s.RunAmendments()
+	assert.Equal(t, amendment, bike.GetConstitution(), "invalid amendments should be rejected")
+
+	// a dictator amends the constitution by decree, and is the only one who can
+	bike.SetGovernance(utils.Dictatorship)
+	bike.SetRuler(citizens[1].GetID())
+	decree := original
+	decree.LootAllocation = utils.EqualAllocation
+	citizens[0].amendment = &amendment
+	citizens[1].amendment = &decree
+	for _, c := range citizens {
+		c.approves = false
+	}
+	s.RunAmendments()
+	assert.Equal(t, decree, bike.GetConstitution())
+	assert.Equal(t, decree, s.NewGameStateDump(0).Bikes[bike.GetID()].Constitution)
+
+	// a bike without a dictator cannot be amended
+	bike.SetRuler(uuid.Nil)
+	assert.NotPanics(t, s.RunAmendments)
+	assert.Equal(t, decree, bike.GetConstitution())
+}
+
+func TestRulerTerm(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.ReferendumPeriod = 1
+	config.RulerTerm = 2
+	config.RoundIterations = 6
+	s := initializeCitizens(t, config)
+	s.SetOutputOptions(server.OutputOptions{})
+	for _, agent := range s.GetAgentMap() {
+		agent.(*citizen).vote = utils.Leadership
+	}
+
+	led := 0
+	for _, gameState := range s.PlayGames()[0] {
+		for _, bike := range gameState.Bikes {
+			if bike.Governance == utils.Leadership && len(bike.AgentIDs) != 0 {
+				led++
+				assert.Less(t, bike.RulerTenure, config.RulerTerm, "leaders should face an election at the end of their term")
+			}
+		}
+	}
+	assert.NotZero(t, led)
+}
+
+func TestImpeachment(t *testing.T) {
+	s := initializeCitizens(t, utils.DefaultSimConfig())
+	bike := s.GetMegaBikes()[utils.SortedIDs(s.GetMegaBikes())[0]]
+	citizens := make([]*citizen, 0)
+	for _, id := range utils.SortedIDs(s.GetAgentMap())[:4] {
+		rider := s.GetAgentMap()[id]
+		rider.SetBike(bike.GetID())
+		s.AddAgentToBike(rider)
+		citizens = append(citizens, rider.(*citizen))
+	}
+	s.UpdateGameStates()
+	bike.SetGovernance(utils.Leadership)
+	ruler := citizens[0].GetID()
+	bike.SetRuler(ruler)
+
+	citizens[0].noConfidence = true
+	citizens[1].noConfidence = true
+	s.RunImpeachments()
+	assert.Equal(t, ruler, bike.GetRuler(), "the ruler should not take part in its impeachment")
+	assert.Empty(t, s.GetElectionHistory(bike.GetID()))
+
+	citizens[2].noConfidence = true
+	s.RunImpeachments()
+	assert.NotEqual(t, ruler, bike.GetRuler(), "an impeached ruler should not be re-elected")
+	assert.Contains(t, utils.SortedIDs(s.GetAgentMap())[:4], bike.GetRuler())
+	history := s.GetElectionHistory(bike.GetID())
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, server.ImpeachmentElection, history[0].Reason)
+		assert.Equal(t, ruler, history[0].Previous)
+		assert.Equal(t, bike.GetRuler(), history[0].Ruler)
+	}
+	assert.Equal(t, history, s.NewGameStateDump(0).Bikes[bike.GetID()].Elections)
+}
+
+func TestRulerElectionMethod(t *testing.T) {
+	// the first candidate wins a borda count, the second a plurality vote
+	for method, winner := range map[utils.VoteMethod]int{utils.PLURALITY: 1, utils.BORDACOUNT: 0} {
+		s := initializeCitizens(t, utils.DefaultSimConfig())
+		bike := s.GetMegaBikes()[utils.SortedIDs(s.GetMegaBikes())[0]]
+		citizens := make([]*citizen, 0)
+		for _, id := range utils.SortedIDs(s.GetAgentMap())[:5] {
+			rider := s.GetAgentMap()[id]
+			rider.SetBike(bike.GetID())
+			s.AddAgentToBike(rider)
+			citizens = append(citizens, rider.(*citizen))
+		}
+		s.UpdateGameStates()
+		for i, c := range citizens {
+			if i < 3 {
+				c.leader = voting.IdVoteMap{citizens[0].GetID(): 0.4, citizens[1].GetID(): 0.35, citizens[2].GetID(): 0.25}
+			} else {
+				c.leader = voting.IdVoteMap{citizens[1].GetID(): 0.9, citizens[0].GetID(): 0.06, citizens[2].GetID(): 0.04}
+			}
+			c.noConfidence = true
+		}
+		constitution := bike.GetConstitution()
+		constitution.DirectionVoting = method
+		bike.SetConstitution(constitution)
+		bike.SetGovernance(utils.Leadership)
+		bike.SetRuler(citizens[2].GetID())
+
+		s.RunImpeachments()
+		assert.Equal(t, citizens[winner].GetID(), bike.GetRuler(), "rulers should be elected with the voting method %s of the constitution", method)
+	}
+}
+
+func TestTermLimit(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.ReferendumPeriod = 1
+	config.RulerTerm = 1
+	config.TermLimit = 1
+	config.RoundIterations = 6
+	s := initializeCitizens(t, config)
+	s.SetOutputOptions(server.OutputOptions{})
+	for _, agent := range s.GetAgentMap() {
+		agent.(*citizen).vote = utils.Leadership
+	}
+
+	terms := 0
+	for round, gameState := range s.PlayGames()[0] {
+		for _, bike := range gameState.Bikes {
+			// a rider alone on its bike stays its ruler
+			if len(bike.AgentIDs) < 2 {
+				continue
+			}
+			for _, election := range bike.Elections {
+				if election.Reason == server.TermElection && election.Round == round-1 {
+					terms++
+					assert.NotEqual(t, election.Previous, election.Ruler, "rulers should not serve more terms than the limit")
+				}
+			}
+		}
+	}
+	assert.NotZero(t, terms)
+}
+
+func TestCouncilReferendum(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.ReferendumPetition = 0.5
+	config.CouncilSize = 2
+	s := initializeCitizens(t, config)
+	bike := s.GetMegaBikes()[utils.SortedIDs(s.GetMegaBikes())[0]]
+	riders := utils.SortedIDs(s.GetAgentMap())[:4]
+	for _, id := range riders {
+		rider := s.GetAgentMap()[id]
+		rider.SetBike(bike.GetID())
+		s.AddAgentToBike(rider)
+		rider.(*citizen).vote = utils.Council
+		rider.(*citizen).petition = true
+	}
+	s.UpdateGameStates()
+
+	s.RunReferendums()
+	assert.Equal(t, utils.Council, bike.GetGovernance())
+	assert.Equal(t, uuid.Nil, bike.GetRuler())
+	assert.Len(t, bike.GetCouncil(), 2)
+	assert.Subset(t, riders, bike.GetCouncil(), "the council should be elected amongst the riders")
+	history := s.GetElectionHistory(bike.GetID())
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, server.ReferendumElection, history[0].Reason)
+		assert.Equal(t, bike.GetCouncil(), history[0].Council)
+	}
+
+	// only the council votes on kickouts, and a member kicked out makes way for a new election
+	constitution := bike.GetConstitution()
+	constitution.KickoutThreshold = 0.4
+	bike.SetConstitution(constitution)
+	council := bike.GetCouncil()
+	for _, id := range riders {
+		if !slices.Contains(council, id) {
+			s.GetAgentMap()[id].(*citizen).ousts = council[1]
+		}
+	}
+	s.GetAgentMap()[council[1]].(*citizen).ousts = council[0]
+	assert.Equal(t, []uuid.UUID{council[0]}, s.HandleKickoutProcess())
+	assert.NotContains(t, bike.GetCouncil(), council[0])
+	assert.Len(t, bike.GetCouncil(), 2)
+
+	for _, id := range riders {
+		s.GetAgentMap()[id].(*citizen).vote = utils.Democracy
+	}
+	s.RunReferendums()
+	assert.Equal(t, utils.Democracy, bike.GetGovernance())
+	assert.Empty(t, bike.GetCouncil())
+}
+
+func TestLeavingDictator(t *testing.T) {
+	s := initializeCitizens(t, utils.DefaultSimConfig())
+	bike, citizens := boardCitizens(s, 4)
+	bike.SetGovernance(utils.Dictatorship)
+	bike.SetRuler(citizens[0].GetID())
+	citizens[0].leaves = true
+
+	assert.Equal(t, []uuid.UUID{citizens[0].GetID()}, s.GetLeavingDecisions(s.NewGameStateDump(0)))
+	assert.Equal(t, utils.Dictatorship, bike.GetGovernance())
+	assert.NotEqual(t, citizens[0].GetID(), bike.GetRuler())
+	history := s.GetElectionHistory(bike.GetID())
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, server.LeavingElection, history[0].Reason)
+		assert.Equal(t, utils.Dictatorship, history[0].Governance, "the dictator should be replaced by a new dictator")
+		assert.Equal(t, bike.GetRuler(), history[0].Ruler)
+	}
+}
+
+func TestCouncilVacancy(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.ReferendumPetition = 0.5
+	config.CouncilSize = 2
+	s := initializeCitizens(t, config)
+	bike, citizens := boardCitizens(s, 5)
+	for _, c := range citizens {
+		c.vote = utils.Council
+		c.petition = true
+	}
+	s.RunReferendums()
+	assert.Equal(t, utils.Council, bike.GetGovernance())
+	bike.IncrementRulerTenure()
+	bike.IncrementRulerTenure()
+
+	// a member kicked out mid-term makes way for a new member, the other member goes on with its term
+	constitution := bike.GetConstitution()
+	constitution.KickoutThreshold = 0.4
+	bike.SetConstitution(constitution)
+	council := bike.GetCouncil()
+	for _, c := range citizens {
+		c.ousts = council[0]
+	}
+	s.GetAgentMap()[council[0]].(*citizen).ousts = uuid.Nil
+	assert.Equal(t, []uuid.UUID{council[0]}, s.HandleKickoutProcess())
+	assert.Len(t, bike.GetCouncil(), 2)
+	assert.Equal(t, council[1], bike.GetCouncil()[0], "the sitting member should keep its seat")
+	assert.Equal(t, 1, bike.GetCouncilTerms(council[1]), "filling a vacancy should not start a new term")
+	assert.Equal(t, 1, bike.GetCouncilTerms(bike.GetCouncil()[1]))
+	assert.Equal(t, 2, bike.GetRulerTenure(), "filling a vacancy should not reset the tenure of the council")
+	history := s.GetElectionHistory(bike.GetID())
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, server.KickoutElection, history[1].Reason)
+		assert.Equal(t, bike.GetCouncil(), history[1].Council)
+	}
+}
+
+func TestCouncilTermLimit(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.Seed = 5
+	config.RulerTerm = 1
+	config.TermLimit = 1
+	config.CouncilSize = 2
+	config.RoundIterations = 6
+	s := initializeCitizens(t, config)
+	s.SetOutputOptions(server.OutputOptions{})
+	for _, agent := range s.GetAgentMap() {
+		agent.(*citizen).founding = utils.Council
+	}
+
+	terms := 0
+	gameStates := s.PlayGames()[0]
+	for round := 1; round < len(gameStates); round++ {
+		for id, bike := range gameStates[round].Bikes {
+			// term limited members keep their seats when there are not enough other riders
+			if len(gameStates[round-1].Bikes[id].AgentIDs) <= 2*config.CouncilSize {
+				continue
+			}
+			for i, election := range bike.Elections {
+				if i > 0 && election.Reason == server.TermElection && election.Round == round-1 {
+					terms++
+					for _, member := range bike.Elections[i-1].Council {
+						assert.NotContains(t, election.Council, member, "council members should not serve more terms than the limit")
+					}
+				}
+			}
+		}
+	}
+	assert.NotZero(t, terms)
+}
+
+func TestCouncilGovernance(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.RoundIterations = 5
+	s := initializeCitizens(t, config)
+	s.SetOutputOptions(server.OutputOptions{})
+	for _, agent := range s.GetAgentMap() {
+		agent.(*citizen).founding = utils.Council
+	}
+
+	councils := 0
+	for round, gameState := range s.PlayGames()[0] {
+		for id, bike := range gameState.Bikes {
+			if len(bike.AgentIDs) == 0 {
+				continue
+			}
+			// riders may move to bikes no one founded, which are democracies
+			if round == 0 {
+				assert.Equal(t, utils.Council, bike.Governance, "bike %s should have been founded as a council", id)
+			}
+			if bike.Governance != utils.Council {
+				continue
+			}
+			assert.Len(t, bike.Council, min(config.CouncilSize, len(bike.AgentIDs)))
+			assert.Subset(t, bike.AgentIDs, bike.Council, "council members should ride the bike")
+			councils++
+		}
+	}
+	assert.NotZero(t, councils)
+}
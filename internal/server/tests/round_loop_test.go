@@ -214,8 +214,11 @@ type INegativeAgent interface {
 	DecideWeights(utils.Action) map[uuid.UUID]float64
 }
 
+// negative agents draw from a seeded generator, so that they are different but the same in every run
+var negativeRand = utils.NewRand(1)
+
 func NewNegativeAgent() *NegativeAgent {
-	baseBiker := objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New())
+	baseBiker := objects.GetBaseBiker(utils.NumOfColours, uuid.New(), negativeRand)
 
 	return &NegativeAgent{
 		BaseBiker: baseBiker,
@@ -653,3 +656,227 @@ func TestLootboxShareDictator(t *testing.T) {
 	}
 
 }
+
+func TestBoundedWorldKeepsObjectsOnTheGrid(t *testing.T) {
+	for _, mode := range []utils.BoundaryMode{utils.Clamp, utils.Reflect, utils.Torus} {
+		config := utils.DefaultSimConfig()
+		config.Boundary = mode
+		config.RoundIterations = 100
+		config.Seed = 3
+		s, err := server.InitializeWithConfig(1, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetOutputOptions(server.OutputOptions{})
+
+		onGrid := func(position utils.Coordinates) bool {
+			return position.X >= 0 && position.X <= config.GridWidth && position.Y >= 0 && position.Y <= config.GridHeight
+		}
+		for _, gameState := range s.PlayGames()[0] {
+			for id, bike := range gameState.Bikes {
+				if !onGrid(bike.PhysicalState.Position) {
+					t.Errorf("%s: bike %s left the grid at %v in round %d", mode, id, bike.PhysicalState.Position, gameState.Iteration)
+				}
+			}
+			if !onGrid(gameState.Audi.PhysicalState.Position) {
+				t.Errorf("%s: audi left the grid at %v in round %d", mode, gameState.Audi.PhysicalState.Position, gameState.Iteration)
+			}
+		}
+	}
+}
+
+func TestMaxVelocityIsEnforced(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.Drag = utils.LinearDrag
+	config.MaxVelocity = 0.5
+	config.RoundIterations = 30
+	config.Seed = 3
+	s, err := server.InitializeWithConfig(1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetOutputOptions(server.OutputOptions{})
+
+	for _, gameState := range s.PlayGames()[0] {
+		for id, bike := range gameState.Bikes {
+			if bike.PhysicalState.Velocity > config.MaxVelocity {
+				t.Errorf("bike %s went at %f in round %d", id, bike.PhysicalState.Velocity, gameState.Iteration)
+			}
+		}
+		assert.LessOrEqual(t, gameState.Audi.PhysicalState.Velocity, config.MaxVelocity)
+	}
+}
+
+// moveTo makes the object travel from start to end in its last step
+func moveTo(object objects.IPhysicsObject, start utils.Coordinates, end utils.Coordinates) {
+	object.SetPhysicalState(utils.PhysicalState{Position: start, Mass: utils.MassBike})
+	object.SetPhysicalState(utils.PhysicalState{Position: end, Mass: utils.MassBike})
+}
+
+func TestLootboxGoesToFirstBikeToReachIt(t *testing.T) {
+	s := server.Initialize(1)
+	s.SetLootSharingPolicy(&objects.FirstArrivalPolicy{})
+	lootboxes := s.GetLootBoxes()
+	lootboxIDs := utils.SortedIDs(lootboxes)
+	for _, id := range lootboxIDs[1:] {
+		delete(lootboxes, id)
+	}
+	lootbox := lootboxes[lootboxIDs[0]]
+	at := func(x float64, y float64) utils.Coordinates {
+		return utils.Coordinates{X: lootbox.GetPosition().X + x, Y: lootbox.GetPosition().Y + y}
+	}
+
+	bikeIDs := utils.SortedIDs(s.GetMegaBikes())
+	for _, id := range bikeIDs[2:] {
+		moveTo(s.GetMegaBikes()[id], at(1000, 1000), at(1000, 1000))
+	}
+	// the fast bike goes through the lootbox, reaching it after a third of the step, before the slow one
+	fastBike, slowBike := s.GetMegaBikes()[bikeIDs[0]], s.GetMegaBikes()[bikeIDs[1]]
+	moveTo(fastBike, at(-30, 0), at(30, 0))
+	moveTo(slowBike, at(0, -10), at(0, -5))
+
+	agents := s.GetAgentMap()
+	agentIDs := utils.SortedIDs(agents)
+	fastRider, slowRider := agents[agentIDs[0]], agents[agentIDs[1]]
+	for bikeID, rider := range map[uuid.UUID]objects.IBaseBiker{fastBike.GetID(): fastRider, slowBike.GetID(): slowRider} {
+		rider.SetBike(bikeID)
+		s.AddAgentToBike(rider)
+		rider.UpdateEnergyLevel(-0.5)
+	}
+	s.UpdateGameStates()
+	s.LootboxCheckAndDistributions()
+
+	assert.Empty(t, s.GetLootBoxes(), "the lootbox should have been looted")
+	assert.Greater(t, fastRider.GetEnergyLevel(), 0.5, "the first bike to reach the lootbox should get it")
+	assert.Equal(t, 0.5, slowRider.GetEnergyLevel(), "bikes reaching the lootbox later should not get anything")
+}
+
+// fixedEnergyModel charges the same energy whatever the agents do
+type fixedEnergyModel struct{}
+
+func (fixedEnergyModel) RidingCost(utils.Forces, objects.IMegaBike) float64 { return 0.25 }
+func (fixedEnergyModel) GovernanceCost(utils.Governance) float64            { return 0.125 }
+func (fixedEnergyModel) BasalCost() float64                                 { return 0.0 }
+func (fixedEnergyModel) LimboCost() float64                                 { return 0.0 }
+
+func TestActionProcessChargesEnergyModelCosts(t *testing.T) {
+	s, err := server.InitializeWithRegistry(1, utils.DefaultSimConfig(), server.AgentRegistry{{Name: "base", InitFunc: nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetEnergyModel(fixedEnergyModel{})
+	s.FoundingInstitutions()
+
+	energy := make(map[uuid.UUID]float64)
+	for id, agent := range s.GetAgentMap() {
+		energy[id] = agent.GetEnergyLevel()
+	}
+	s.RunActionProcess()
+
+	riders := 0
+	for _, bike := range s.GetMegaBikes() {
+		for _, agent := range bike.GetAgents() {
+			riders++
+			assert.Equal(t, energy[agent.GetID()]-0.375, agent.GetEnergyLevel())
+		}
+	}
+	assert.NotZero(t, riders)
+}
+
+func TestLootBoxesAreNotReplenishedDuringScarcity(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootBoxScarcityPeriod = 5
+	config.LootBoxScarcityRounds = 5
+	config.RoundIterations = 30
+	config.Seed = 3
+	s, err := server.InitializeWithConfig(1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetOutputOptions(server.OutputOptions{})
+
+	gameStates := s.PlayGames()[0]
+	assert.Len(t, gameStates[0].LootBoxes, config.LootBoxCount, "games should start with every lootbox")
+	for i := 1; i < len(gameStates); i++ {
+		assert.LessOrEqual(t, len(gameStates[i].LootBoxes), len(gameStates[i-1].LootBoxes), "no lootbox should be replenished in round %d", i)
+	}
+	assert.Less(t, len(gameStates[len(gameStates)-1].LootBoxes), config.LootBoxCount, "some lootboxes should have been looted")
+}
+
+func TestLootBoxLifecycle(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootBoxLifetime = 3
+	config.LootBoxDecay = 0.1
+	config.LootBoxDriftForce = 0.5
+	config.RoundIterations = 5
+	config.Seed = 3
+	s, err := server.InitializeWithConfig(1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetOutputOptions(server.OutputOptions{})
+
+	gameStates := s.PlayGames()[0]
+	for id, lootBox := range gameStates[1].LootBoxes {
+		initial, ok := gameStates[0].LootBoxes[id]
+		if !ok {
+			continue // replaces a looted lootbox
+		}
+		assert.Equal(t, 2, lootBox.TimeToLive)
+		assert.InDelta(t, 0.9*initial.TotalResources, lootBox.TotalResources, 1e-9, "lootbox %s should have decayed", id)
+		assert.NotEqual(t, initial.PhysicalState.Position, lootBox.PhysicalState.Position, "lootbox %s should have drifted", id)
+	}
+	// the lootboxes that expired have been replaced
+	assert.Len(t, gameStates[3].LootBoxes, config.LootBoxCount)
+	for id := range gameStates[0].LootBoxes {
+		assert.NotContains(t, gameStates[3].LootBoxes, id, "lootbox %s should have expired", id)
+	}
+}
+
+func TestContestedLootboxSharing(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.LootSharing = utils.RiderShare
+	s, err := server.InitializeWithRegistry(1, config, server.AgentRegistry{{Name: "base", InitFunc: nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lootboxes := s.GetLootBoxes()
+	lootboxIDs := utils.SortedIDs(lootboxes)
+	for _, id := range lootboxIDs[1:] {
+		delete(lootboxes, id)
+	}
+	lootbox := lootboxes[lootboxIDs[0]]
+	at := func(x float64, y float64) utils.Coordinates {
+		return utils.Coordinates{X: lootbox.GetPosition().X + x, Y: lootbox.GetPosition().Y + y}
+	}
+
+	bikeIDs := utils.SortedIDs(s.GetMegaBikes())
+	for _, id := range bikeIDs[2:] {
+		moveTo(s.GetMegaBikes()[id], at(1000, 1000), at(1000, 1000))
+	}
+	// both bikes reach the lootbox during the step, the second one later
+	firstBike, secondBike := s.GetMegaBikes()[bikeIDs[0]], s.GetMegaBikes()[bikeIDs[1]]
+	moveTo(firstBike, at(-30, 0), at(30, 0))
+	moveTo(secondBike, at(0, -10), at(0, 0))
+
+	agentIDs := utils.SortedIDs(s.GetAgentMap())
+	riders := map[uuid.UUID][]uuid.UUID{firstBike.GetID(): agentIDs[:2], secondBike.GetID(): agentIDs[2:3]}
+	for bikeID, ids := range riders {
+		for _, id := range ids {
+			rider := s.GetAgentMap()[id]
+			rider.SetBike(bikeID)
+			s.AddAgentToBike(rider)
+		}
+	}
+	s.UpdateGameStates()
+	s.LootboxCheckAndDistributions()
+
+	assert.Empty(t, s.GetLootBoxes(), "the lootbox should have been looted")
+	shares := s.NewGameStateDump(0).LootShares
+	if assert.Len(t, shares, 1) {
+		assert.Equal(t, lootbox.GetID(), shares[0].LootBox)
+		assert.Len(t, shares[0].Contestants, 2)
+		assert.InDelta(t, 2.0/3.0, shares[0].Shares[firstBike.GetID()], 1e-9, "the share should be proportional to the riders")
+		assert.InDelta(t, 1.0/3.0, shares[0].Shares[secondBike.GetID()], 1e-9)
+	}
+}
@@ -0,0 +1,145 @@
+package server_test
+
+import (
+	"SOMAS2023/internal/common/objects"
+	"SOMAS2023/internal/common/utils"
+	"SOMAS2023/internal/server"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// boardCitizens puts the first citizens of the server on the first bike
+func boardCitizens(s server.IBaseBikerServer, count int) (objects.IMegaBike, []*citizen) {
+	bike := s.GetMegaBikes()[utils.SortedIDs(s.GetMegaBikes())[0]]
+	citizens := make([]*citizen, 0, count)
+	for _, id := range utils.SortedIDs(s.GetAgentMap())[:count] {
+		rider := s.GetAgentMap()[id]
+		rider.SetBike(bike.GetID())
+		s.AddAgentToBike(rider)
+		citizens = append(citizens, rider.(*citizen))
+	}
+	s.UpdateGameStates()
+	return bike, citizens
+}
+
+func TestGraduatedSanctions(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	s := initializeCitizens(t, config)
+	bike, citizens := boardCitizens(s, 4)
+	offender := citizens[3]
+
+	citizens[0].accuses, citizens[1].accuses = offender.GetID(), offender.GetID()
+	assert.Empty(t, s.RunSanctions())
+	assert.Empty(t, s.GetSanctionHistory(offender.GetID()), "half the votes should not be enough to sanction a rider")
+
+	citizens[2].accuses = offender.GetID()
+	energy := offender.GetEnergyLevel()
+	for _, sanction := range []utils.SanctionType{utils.WarningSanction, utils.FineSanction, utils.VoteWeightSanction, utils.LootExclusionSanction} {
+		assert.Empty(t, s.RunSanctions())
+		sanctions := s.NewGameStateDump(0).Sanctions
+		if assert.Len(t, sanctions, 1) {
+			assert.Equal(t, sanction, sanctions[0].Sanction, "sanctions should escalate with the offences")
+			assert.Equal(t, offender.GetID(), sanctions[0].Agent)
+		}
+	}
+	assert.InDelta(t, energy-config.SanctionFine, offender.GetEnergyLevel(), 1e-9)
+	assert.InDelta(t, config.SanctionFine, bike.GetFinePool(), 1e-9, "the fine should be paid into the fine pool of the bike")
+
+	assert.Equal(t, []uuid.UUID{offender.GetID()}, s.RunSanctions())
+	assert.NotContains(t, bike.GetAgents(), offender, "the last sanction should be a kickout")
+	history := s.GetSanctionHistory(offender.GetID())
+	if assert.Len(t, history, 5) {
+		assert.Equal(t, utils.KickoutSanction, history[4].Sanction)
+		assert.Equal(t, 5, history[4].Offence)
+	}
+}
+
+func TestRulerSanctions(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.SanctionLadder = []utils.SanctionType{utils.FineSanction}
+	s := initializeCitizens(t, config)
+	bike, citizens := boardCitizens(s, 4)
+	bike.SetGovernance(utils.Leadership)
+	bike.SetRuler(citizens[0].GetID())
+
+	// only the ruler decides on sanctions, and cannot be sanctioned
+	for _, c := range citizens[1:] {
+		c.accuses = citizens[0].GetID()
+	}
+	s.RunSanctions()
+	assert.Empty(t, s.GetSanctionHistory(citizens[0].GetID()))
+
+	citizens[0].accuses = citizens[1].GetID()
+	s.RunSanctions()
+	s.RunSanctions()
+	history := s.GetSanctionHistory(citizens[1].GetID())
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, utils.FineSanction, history[1].Sanction, "offences past the end of the ladder should get its last sanction")
+	}
+	assert.InDelta(t, 2*config.SanctionFine, bike.GetFinePool(), 1e-9)
+}
+
+func TestLootExclusion(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.SanctionLadder = []utils.SanctionType{utils.LootExclusionSanction}
+	s := initializeCitizens(t, config)
+	bike, citizens := boardCitizens(s, 2)
+	lootboxes := s.GetLootBoxes()
+	lootboxIDs := utils.SortedIDs(lootboxes)
+	for _, id := range lootboxIDs[1:] {
+		delete(lootboxes, id)
+	}
+	lootbox := lootboxes[lootboxIDs[0]]
+	for _, id := range utils.SortedIDs(s.GetMegaBikes())[1:] {
+		moveTo(s.GetMegaBikes()[id], utils.Coordinates{X: 1000, Y: 1000}, utils.Coordinates{X: 1000, Y: 1000})
+	}
+	moveTo(bike, lootbox.GetPosition(), lootbox.GetPosition())
+
+	citizens[0].accuses = citizens[1].GetID()
+	citizens[1].accuses = citizens[1].GetID()
+	s.RunSanctions()
+	for _, c := range citizens {
+		c.UpdateEnergyLevel(-0.9)
+	}
+	excludedEnergy := citizens[1].GetEnergyLevel()
+	resources := lootbox.GetTotalResources()
+	s.UpdateGameStates()
+	s.LootboxCheckAndDistributions()
+
+	assert.Empty(t, s.GetLootBoxes(), "the lootbox should have been looted")
+	assert.Equal(t, excludedEnergy, citizens[1].GetEnergyLevel(), "an excluded rider should get no loot")
+	assert.InDelta(t, resources/2, bike.GetFinePool(), 1e-9, "the share of an excluded rider should go to the fine pool")
+}
+
+func TestVoteWeightSanction(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.SanctionLadder = []utils.SanctionType{utils.VoteWeightSanction}
+	s := initializeCitizens(t, config)
+	bike, citizens := boardCitizens(s, 5)
+
+	// the first two riders have their vote weight halved
+	for _, accused := range citizens[:2] {
+		for _, c := range citizens {
+			c.accuses = accused.GetID()
+		}
+		accused.accuses = uuid.Nil
+		s.RunSanctions()
+	}
+	for _, c := range citizens {
+		c.accuses = uuid.Nil
+	}
+
+	// three votes would kick the last rider out, but two of them only count for half
+	for _, c := range citizens[:3] {
+		c.ousts = citizens[4].GetID()
+	}
+	assert.Empty(t, s.HandleKickoutProcess(), "the votes of sanctioned riders should count for less in kickouts")
+	assert.Contains(t, bike.GetAgents(), citizens[4])
+
+	// the threshold is a fraction of the total weight of the riders, not of their number
+	citizens[1].ousts = uuid.Nil
+	citizens[3].ousts = citizens[4].GetID()
+	assert.Equal(t, []uuid.UUID{citizens[4].GetID()}, s.HandleKickoutProcess())
+}
@@ -1,8 +1,11 @@
 package server_test
 
 import (
+	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/server"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
@@ -12,16 +15,17 @@ func TestInitialize(t *testing.T) {
 
 	it := 3
 	s := server.Initialize(it)
+	config := s.GetConfig()
 
-	if len(s.GetAgentMap()) != server.BikerAgentCount {
+	if len(s.GetAgentMap()) != config.BikerAgentCount {
 		t.Error("Agents not properly instantiated")
 	}
 
-	if len(s.GetMegaBikes()) != server.MegaBikeCount {
+	if len(s.GetMegaBikes()) != config.MegaBikeCount {
 		t.Error("mega bikes not properly instantiated")
 	}
 
-	if len(s.GetLootBoxes()) != server.LootBoxCount {
+	if len(s.GetLootBoxes()) != config.LootBoxCount {
 		t.Error("Mega bikes not properly instantiated")
 	}
 
@@ -33,5 +37,82 @@ func TestInitialize(t *testing.T) {
 }
 
 func TestRunGame(t *testing.T) {
-	server.Initialize(1).Start()
+	s := server.Initialize(1)
+	options := server.DefaultOutputOptions()
+	options.Directory = t.TempDir()
+	s.SetOutputOptions(options)
+	s.Start()
+}
+
+func TestSameSeedSameGame(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.RoundIterations = 30
+	config.Seed = 42
+
+	runGame := func() []byte {
+		directory := t.TempDir()
+		s, err := server.InitializeWithConfig(1, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetOutputOptions(server.OutputOptions{Directory: directory, GameDump: true})
+		if err := s.Run(); err != nil {
+			t.Fatal(err)
+		}
+		dump, err := os.ReadFile(filepath.Join(directory, server.GameDumpFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dump
+	}
+
+	if string(runGame()) != string(runGame()) {
+		t.Error("games played with the same seed differ")
+	}
+}
+
+func TestRandomSeedIsRecorded(t *testing.T) {
+	s := server.Initialize(1)
+	if s.GetConfig().Seed == 0 {
+		t.Error("the seed picked by the server should be readable from its config")
+	}
+}
+
+func TestSimulationsRunConcurrently(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.RoundIterations = 20
+	simulations := make([]server.Simulation, 4)
+	for i := range simulations {
+		config.Seed = int64(i%2 + 1)
+		simulations[i] = server.Simulation{
+			Iterations: 1,
+			Config:     config,
+			Output:     server.OutputOptions{Directory: filepath.Join(t.TempDir(), fmt.Sprint(i)), GameDump: true},
+		}
+	}
+
+	serial, err := server.RunSimulations(simulations, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	concurrent, err := server.RunSimulations(simulations, len(simulations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range simulations {
+		if serial[i] != concurrent[i] {
+			t.Errorf("simulation %d: %+v when run alone, %+v when run concurrently", i, serial[i], concurrent[i])
+		}
+		if concurrent[i] != concurrent[i%2] {
+			t.Errorf("simulation %d differs from the other simulation with seed %d", i, concurrent[i].Seed)
+		}
+		if _, err := os.Stat(filepath.Join(simulations[i].Output.Directory, server.GameDumpFileName)); err != nil {
+			t.Errorf("simulation %d did not write its game dump: %v", i, err)
+		}
+	}
+
+	simulations[1].Config.TeamCounts = map[string]int{"team42": 1}
+	if _, err := server.RunSimulations(simulations, 2); err == nil {
+		t.Error("the error of a failed simulation should be returned")
+	}
 }
@@ -26,8 +26,11 @@ type MegaBike struct {
 	kickedOutCount int
 }
 
+// mock bikers draw from a seeded generator, so that they are different but the same in every run
+var mockRand = utils.NewRand(1)
+
 func NewMockBiker() *MockBiker {
-	baseBiker := objects.GetBaseBiker(utils.GenerateRandomColour(), uuid.New())
+	baseBiker := objects.GetBaseBiker(utils.NumOfColours, uuid.New(), mockRand)
 
 	return &MockBiker{
 		BaseBiker: baseBiker,
@@ -133,10 +136,8 @@ func TestResetGameState(t *testing.T) {
 }
 
 func TestFoundingInstitutions(t *testing.T) {
-	OnlySpawnBaseBikers(t)
-
 	it := 2
-	s := server.Initialize(it)
+	s := InitializeBaseBikers(t, it)
 
 	// remove 4 agents from the map
 	i := 0
@@ -0,0 +1,57 @@
+package server_test
+
+import (
+	"SOMAS2023/internal/common/utils"
+	"SOMAS2023/internal/server"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamCountsSplitEvenly(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.BikerAgentCount = len(server.AgentTeamNames())*3 + 2
+
+	teamCounts, err := server.GetTeamCounts(config)
+	assert.NoError(t, err)
+	total := 0
+	for i, team := range server.AgentTeamNames() {
+		// the remainder goes to the first teams rather than being dropped
+		if i < 2 {
+			assert.Equal(t, 4, teamCounts[team])
+		} else {
+			assert.Equal(t, 3, teamCounts[team])
+		}
+		total += teamCounts[team]
+	}
+	assert.Equal(t, config.BikerAgentCount, total)
+}
+
+func TestTeamCountsFromConfig(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.TeamCounts = map[string]int{"team1": 10, "team5": 2, "base": 0}
+
+	teamCounts, err := server.GetTeamCounts(config)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, teamCounts["team1"])
+	assert.Equal(t, 2, teamCounts["team5"])
+	assert.Equal(t, 0, teamCounts["team8"], "teams that are not listed should not be spawned")
+
+	s, err := server.InitializeWithConfig(1, config)
+	assert.NoError(t, err)
+	groups := make(map[int]int)
+	for _, agent := range s.GetAgentMap() {
+		groups[agent.GetGroupID()]++
+	}
+	assert.Equal(t, map[int]int{1: 10, 5: 2}, groups)
+}
+
+func TestUnknownTeamIsRejected(t *testing.T) {
+	config := utils.DefaultSimConfig()
+	config.TeamCounts = map[string]int{"team42": 4}
+
+	_, err := server.GetTeamCounts(config)
+	assert.Error(t, err)
+	_, err = server.InitializeWithConfig(1, config)
+	assert.Error(t, err)
+}
@@ -1,14 +1,16 @@
 package server
 
 import (
+	"SOMAS2023/internal/common/utils"
 	"SOMAS2023/internal/server"
 	"testing"
 )
 
-func OnlySpawnBaseBikers(t *testing.T) {
-	oldInitFunctions := server.AgentInitFunctions
-	server.AgentInitFunctions = []server.AgentInitFunction{nil}
-	t.Cleanup(func() {
-		server.AgentInitFunctions = oldInitFunctions
-	})
+// InitializeBaseBikers creates a server that only spawns base bikers
+func InitializeBaseBikers(t *testing.T, iterations int) server.IBaseBikerServer {
+	s, err := server.InitializeWithRegistry(iterations, utils.DefaultSimConfig(), server.AgentRegistry{{Name: "base", InitFunc: nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
 }
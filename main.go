@@ -1,13 +1,13 @@
 package main
 
 import (
-	"SOMAS2023/internal/server"
 	"fmt"
+	"os"
 )
 
 func main() {
-	fmt.Println("Hello Agents")
-	s := server.Initialize(10)
-	s.UpdateGameStates()
-	s.Start()
+	if err := runCLI(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }